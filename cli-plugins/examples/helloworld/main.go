@@ -41,8 +41,19 @@ func main() {
 			// the path where a plugin overrides this
 			// hook.
 			PersistentPreRunE: plugin.PersistentPreRunE,
-			Run: func(cmd *cobra.Command, args []string) {
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if !cmd.Flags().Changed("who") {
+					// the current context can set who to address
+					ep, err := plugin.CurrentContextEndpoint(dockerCli, "hello")
+					if err != nil {
+						return err
+					}
+					if ep["who"] != "" {
+						who = ep["who"]
+					}
+				}
 				fmt.Fprintf(dockerCli.Out(), "Hello %s!\n", who)
+				return nil
 			},
 		}
 		flags := cmd.Flags()
@@ -55,5 +66,14 @@ func main() {
 			SchemaVersion: "0.1.0",
 			Vendor:        "Docker Inc.",
 			Version:       "testing",
+			EndpointTypes: []manager.EndpointType{
+				{
+					Name:        "hello",
+					Description: "set who the helloworld plugin addresses",
+					Keys: []manager.EndpointKey{
+						{Name: "who", Description: "Who are we addressing?"},
+					},
+				},
+			},
 		})
 }
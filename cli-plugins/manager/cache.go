@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+const (
+	// NoCacheEnvVar is the name of the environment variable which, when set
+	// to a non-empty value, disables the cache of the plugin metadata.
+	NoCacheEnvVar = "DOCKER_CLI_PLUGINS_NO_CACHE"
+
+	metadataCacheFile    = "cli-plugins-metadata.json"
+	metadataCacheVersion = 1
+)
+
+// metadataCache holds the metadata reported by plugin binaries, so that they
+// are not run each time the plugins are listed. An entry is only used while
+// the size and modification time of its binary are unchanged.
+type metadataCache struct {
+	path    string
+	entries map[string]metadataCacheEntry
+	dirty   bool
+}
+
+type metadataCacheFileContent struct {
+	Version int
+	Plugins map[string]metadataCacheEntry
+}
+
+type metadataCacheEntry struct {
+	Size    int64
+	ModTime time.Time
+	// Metadata is the raw output of the metadata subcommand, validated
+	// when the plugin is loaded
+	Metadata string
+}
+
+// loadMetadataCache loads the metadata cache from the configuration directory.
+// It returns nil when the cache is disabled. An unreadable cache is ignored.
+func loadMetadataCache() *metadataCache {
+	if os.Getenv(NoCacheEnvVar) != "" {
+		return nil
+	}
+	c := newMetadataCache(config.Path(metadataCacheFile))
+	if data, err := ioutil.ReadFile(c.path); err == nil {
+		var content metadataCacheFileContent
+		if err := json.Unmarshal(data, &content); err == nil && content.Version == metadataCacheVersion && content.Plugins != nil {
+			c.entries = content.Plugins
+		}
+	}
+	return c
+}
+
+func newMetadataCache(path string) *metadataCache {
+	return &metadataCache{
+		path:    path,
+		entries: map[string]metadataCacheEntry{},
+	}
+}
+
+// reset drops all the entries of the cache, so that every plugin is run again
+// and the cache only holds the plugins which are found
+func (m *metadataCache) reset() {
+	if m == nil {
+		return
+	}
+	m.entries = map[string]metadataCacheEntry{}
+	m.dirty = true
+}
+
+// candidate wraps c so that its metadata is read from the cache. It returns c
+// itself if the cache is nil.
+func (m *metadataCache) candidate(c Candidate) Candidate {
+	if m == nil {
+		return c
+	}
+	return &cachedCandidate{Candidate: c, cache: m}
+}
+
+func (m *metadataCache) metadata(c Candidate) ([]byte, error) {
+	fi, err := os.Stat(c.Path())
+	if err != nil {
+		return c.Metadata()
+	}
+	if e, ok := m.entries[c.Path()]; ok && e.Size == fi.Size() && e.ModTime.Equal(fi.ModTime()) {
+		return []byte(e.Metadata), nil
+	}
+	meta, err := c.Metadata()
+	if err != nil {
+		// failures to run the binary may be transient, so they are not cached
+		return nil, err
+	}
+	m.entries[c.Path()] = metadataCacheEntry{
+		Size:     fi.Size(),
+		ModTime:  fi.ModTime(),
+		Metadata: string(meta),
+	}
+	m.dirty = true
+	return meta, nil
+}
+
+// save writes the cache if it was updated, dropping the entries of the
+// binaries which no longer exist. Errors are ignored, as the cache is only
+// needed to speed up the listing of plugins.
+func (m *metadataCache) save() {
+	if m == nil || !m.dirty {
+		return
+	}
+	for path := range m.entries {
+		if _, err := os.Stat(path); err != nil {
+			delete(m.entries, path)
+		}
+	}
+	data, err := json.Marshal(metadataCacheFileContent{
+		Version: metadataCacheVersion,
+		Plugins: m.entries,
+	})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return
+	}
+	if err := ioutils.AtomicWriteFile(m.path, data, 0600); err == nil {
+		m.dirty = false
+	}
+}
+
+// cachedCandidate is a Candidate whose metadata is read from the cache
+type cachedCandidate struct {
+	Candidate
+	cache *metadataCache
+}
+
+func (c *cachedCandidate) Metadata() ([]byte, error) {
+	return c.cache.metadata(c.Candidate)
+}
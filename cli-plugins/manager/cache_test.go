@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+)
+
+// countingCandidate counts the times the metadata of a candidate is fetched
+type countingCandidate struct {
+	fakeCandidate
+	count int
+}
+
+func (c *countingCandidate) Metadata() ([]byte, error) {
+	c.count++
+	return c.fakeCandidate.Metadata()
+}
+
+func TestMetadataCache(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", "binary"))
+	defer dir.Remove()
+	c := &countingCandidate{fakeCandidate: fakeCandidate{path: dir.Join("docker-test"), exec: true, meta: `{"SchemaVersion": "0.1.0"}`}}
+	cache := newMetadataCache(dir.Join("cache.json"))
+
+	for i := 0; i < 2; i++ {
+		meta, err := cache.candidate(c).Metadata()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(meta), `{"SchemaVersion": "0.1.0"}`))
+	}
+	assert.Check(t, is.Equal(c.count, 1))
+
+	// the entry is invalidated when the binary changes
+	assert.NilError(t, ioutil.WriteFile(c.path, []byte("new binary"), 0755))
+	c.meta = `{"SchemaVersion": "0.2.0"}`
+	meta, err := cache.candidate(c).Metadata()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(meta), `{"SchemaVersion": "0.2.0"}`))
+	assert.Check(t, is.Equal(c.count, 2))
+
+	later := time.Now().Add(time.Hour)
+	assert.NilError(t, os.Chtimes(c.path, later, later))
+	_, err = cache.candidate(c).Metadata()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(c.count, 3))
+}
+
+func TestMetadataCacheFailuresNotCached(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", "binary"))
+	defer dir.Remove()
+	c := &countingCandidate{fakeCandidate: fakeCandidate{path: dir.Join("docker-test"), exec: false}}
+	cache := newMetadataCache(dir.Join("cache.json"))
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.candidate(c).Metadata()
+		assert.ErrorContains(t, err, "faked a failure to exec")
+	}
+	assert.Check(t, is.Equal(c.count, 2))
+	assert.Check(t, !cache.dirty)
+}
+
+func TestMetadataCacheSaveLoad(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", "binary"), fs.WithFile("docker-removed", "binary"))
+	defer dir.Remove()
+	defer env.Patch(t, NoCacheEnvVar, "")()
+	previous := config.Dir()
+	config.SetDir(dir.Join("config"))
+	defer config.SetDir(previous)
+
+	c := &countingCandidate{fakeCandidate: fakeCandidate{path: dir.Join("docker-test"), exec: true, meta: `{"SchemaVersion": "0.1.0"}`}}
+	removed := &countingCandidate{fakeCandidate: fakeCandidate{path: dir.Join("docker-removed"), exec: true, meta: `{}`}}
+	cache := loadMetadataCache()
+	assert.Assert(t, cache != nil)
+	_, err := cache.candidate(c).Metadata()
+	assert.NilError(t, err)
+	_, err = cache.candidate(removed).Metadata()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Remove(removed.path))
+	cache.save()
+	fi, err := os.Stat(filepath.Join(config.Dir(), metadataCacheFile))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+
+	cache = loadMetadataCache()
+	assert.Check(t, is.Len(cache.entries, 1))
+	_, err = cache.candidate(c).Metadata()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(c.count, 1))
+	assert.Check(t, !cache.dirty)
+
+	cache.reset()
+	_, err = cache.candidate(c).Metadata()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(c.count, 2))
+
+	// caches of another version are ignored
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(config.Dir(), metadataCacheFile), []byte(`{"Version": 0, "Plugins": {}}`), 0600))
+	cache = loadMetadataCache()
+	assert.Check(t, is.Len(cache.entries, 0))
+
+	defer env.Patch(t, NoCacheEnvVar, "1")()
+	cache = loadMetadataCache()
+	assert.Check(t, cache == nil)
+	assert.Check(t, cache.candidate(c) == Candidate(c))
+}
@@ -67,8 +67,15 @@ func TestValidateCandidate(t *testing.T) {
 		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "xyzzy"}`}, invalid: `plugin SchemaVersion "xyzzy" is not valid`},
 		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0"}`}, invalid: "plugin metadata does not define a vendor"},
 		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": ""}`}, invalid: "plugin metadata does not define a vendor"},
-		// This one should work
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "Foo", "Keys": [{"Name": "url"}]}]}`}, invalid: `plugin endpoint type "Foo" did not match`},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "docker", "Keys": [{"Name": "url"}]}]}`}, invalid: `plugin endpoint type "docker" duplicates a builtin endpoint type`},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "foo", "Keys": [{"Name": "url"}]}, {"Name": "foo", "Keys": [{"Name": "url"}]}]}`}, invalid: `plugin endpoint type "foo" is declared more than once`},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "foo"}]}`}, invalid: `plugin endpoint type "foo" does not define any key`},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "foo", "Keys": [{"Name": "a=b"}]}]}`}, invalid: `plugin endpoint type "foo" has an invalid key "a=b"`},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "foo", "Keys": [{"Name": "url"}, {"Name": "url"}]}]}`}, invalid: `plugin endpoint type "foo" declares key "url" more than once`},
+		// These ones should work
 		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing"}`}},
+		{c: &fakeCandidate{path: goodPluginPath, exec: true, meta: `{"SchemaVersion": "0.1.0", "Vendor": "e2e-testing", "EndpointTypes": [{"Name": "my-cloud", "Keys": [{"Name": "url"}, {"Name": "token"}]}]}`}},
 	} {
 		p, err := newPlugin(tc.c, fakeroot)
 		if tc.err != "" {
@@ -0,0 +1,292 @@
+package manager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// InstallOptions are the options used to install a plugin
+type InstallOptions struct {
+	// Name of the plugin. Optional if the binary is named after the plugin,
+	// or if the archive holds a single plugin binary.
+	Name string
+	// SHA256 is the expected checksum of the source, in hexadecimal. Optional.
+	SHA256 string
+	// Force replaces a plugin of the same name installed for the user.
+	Force bool
+}
+
+// GetPlugin returns the plugin which is run for name, with the paths of the
+// plugins it shadows. The error satisfies the IsNotFound() predicate if no
+// plugin was found.
+func GetPlugin(dockerCli command.Cli, name string, rootcmd *cobra.Command) (Plugin, error) {
+	candidates, err := listPluginCandidates(getPluginDirs(dockerCli))
+	if err != nil {
+		return Plugin{}, err
+	}
+	paths := candidates[name]
+	if len(paths) == 0 {
+		return Plugin{}, errPluginNotFound(name)
+	}
+	cache := loadMetadataCache()
+	defer cache.save()
+	p, err := newPlugin(cache.candidate(&candidate{paths[0]}), rootcmd)
+	if err != nil {
+		return Plugin{}, err
+	}
+	p.ShadowedPaths = paths[1:]
+	return p, nil
+}
+
+// InstallPlugin installs a plugin in the user plugin directory from source,
+// the path of a plugin binary or of a tar, gzipped tar or zip archive holding
+// it. The binary is validated as a plugin before being installed.
+func InstallPlugin(source string, opts InstallOptions, rootcmd *cobra.Command) (Plugin, error) {
+	if opts.Name != "" {
+		if err := checkPluginName(opts.Name); err != nil {
+			return Plugin{}, err
+		}
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return Plugin{}, err
+	}
+	defer f.Close()
+	if opts.SHA256 != "" {
+		if err := verifyChecksum(f, opts.SHA256); err != nil {
+			return Plugin{}, err
+		}
+	}
+
+	dir := config.UserPluginDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Plugin{}, err
+	}
+	tmpDir, err := ioutil.TempDir(dir, ".install-")
+	if err != nil {
+		return Plugin{}, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	name, err := extractPluginBinary(f, source, opts.Name, tmpDir)
+	if err != nil {
+		return Plugin{}, err
+	}
+	exename := addExeSuffix(NamePrefix + name)
+	dest := filepath.Join(dir, exename)
+	if _, err := os.Stat(dest); err == nil && !opts.Force {
+		return Plugin{}, errors.Errorf("plugin %q is already installed in %s", name, dir)
+	}
+	p, err := newPlugin(&candidate{filepath.Join(tmpDir, exename)}, rootcmd)
+	if err != nil {
+		return Plugin{}, err
+	}
+	if p.Err != nil {
+		return Plugin{}, errors.Wrapf(p.Err, "%s is not a valid CLI plugin", source)
+	}
+	if err := os.Rename(p.Path, dest); err != nil {
+		return Plugin{}, err
+	}
+	p.Path = dest
+	return p, nil
+}
+
+func verifyChecksum(f *os.File, expected string) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return errors.Errorf("checksum mismatch for %s: expected sha256 %s, got %s", f.Name(), expected, actual)
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
+
+// extractPluginBinary writes the plugin binary of source to dir, with its
+// file name, and returns the name of the plugin
+func extractPluginBinary(f *os.File, source string, name string, dir string) (string, error) {
+	r := bufio.NewReader(f)
+	header, _ := r.Peek(512)
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		return extractPluginFromTar(gz, source, name, dir)
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return extractPluginFromTar(r, source, name, dir)
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		fi, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		return extractPluginFromZip(f, fi.Size(), source, name, dir)
+	}
+	if name == "" {
+		var ok bool
+		if name, ok = pluginNameOf(filepath.Base(source)); !ok {
+			return "", errors.Errorf("unable to determine the name of the plugin %s, which is not named %s<name>", source, NamePrefix)
+		}
+		if err := checkPluginName(name); err != nil {
+			return "", err
+		}
+	}
+	return name, writePluginBinary(r, filepath.Join(dir, addExeSuffix(NamePrefix+name)))
+}
+
+func extractPluginFromTar(r io.Reader, source string, name string, dir string) (string, error) {
+	// the archive is read once, so all the plugin binaries it holds are
+	// extracted before the one to install is chosen
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read archive %s", source)
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		n, ok := pluginNameOf(path.Base(hdr.Name))
+		if !ok || (name != "" && n != name) || containsString(names, n) {
+			continue
+		}
+		if err := writePluginBinary(tr, filepath.Join(dir, addExeSuffix(NamePrefix+n))); err != nil {
+			return "", err
+		}
+		names = append(names, n)
+	}
+	return choosePluginName(names, source, name)
+}
+
+func extractPluginFromZip(r io.ReaderAt, size int64, source string, name string, dir string) (string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read archive %s", source)
+	}
+	var names []string
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		n, ok := pluginNameOf(path.Base(zf.Name))
+		if !ok || (name != "" && n != name) || containsString(names, n) {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read archive %s", source)
+		}
+		err = writePluginBinary(rc, filepath.Join(dir, addExeSuffix(NamePrefix+n)))
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+		names = append(names, n)
+	}
+	return choosePluginName(names, source, name)
+}
+
+func choosePluginName(names []string, source string, name string) (string, error) {
+	switch {
+	case len(names) == 1:
+		return names[0], checkPluginName(names[0])
+	case len(names) > 1:
+		return "", errors.Errorf("archive %s holds several plugins (%s), the name of the plugin to install must be set", source, strings.Join(names, ", "))
+	case name != "":
+		return "", errors.Errorf("archive %s does not hold plugin %q", source, name)
+	}
+	return "", errors.Errorf("archive %s does not hold any plugin", source)
+}
+
+// pluginNameOf returns the name of the plugin of a binary, from its file name
+func pluginNameOf(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, NamePrefix) || strings.HasPrefix(fileName, connhelper.PluginNamePrefix) {
+		return "", false
+	}
+	name, err := trimExeSuffix(strings.TrimPrefix(fileName, NamePrefix))
+	if err != nil || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+func checkPluginName(name string) error {
+	if !pluginNameRe.MatchString(name) {
+		return errors.Errorf("plugin name %q did not match %q", name, pluginNameRe.String())
+	}
+	return nil
+}
+
+func writePluginBinary(r io.Reader, dest string) error {
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RemovePlugin removes the plugin which is run for name, and returns its
+// path. Plugins installed in the system wide plugin directories are managed
+// by packages or system administrators, and are not removed.
+func RemovePlugin(dockerCli command.Cli, name string) (string, error) {
+	candidates, err := listPluginCandidates(getPluginDirs(dockerCli))
+	if err != nil {
+		return "", err
+	}
+	paths := candidates[name]
+	if len(paths) == 0 {
+		return "", errPluginNotFound(name)
+	}
+	if dir, ok := systemPluginDirOf(paths[0]); ok {
+		return "", errors.Errorf("plugin %q is installed in the system directory %s, and cannot be removed", name, dir)
+	}
+	return paths[0], os.Remove(paths[0])
+}
+
+// systemPluginDirOf returns the system wide plugin directory holding the
+// plugin binary at path, if any
+func systemPluginDirOf(path string) (string, bool) {
+	for _, dir := range config.SystemPluginDirs() {
+		if filepath.Clean(filepath.Dir(path)) == filepath.Clean(dir) {
+			return dir, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,196 @@
+// +build !windows
+
+package manager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+const testPluginScript = "#!/bin/sh\necho '{\"SchemaVersion\": \"0.1.0\", \"Vendor\": \"e2e-testing\", \"Version\": \"1.0\"}'\n"
+
+func setTestConfigDir(t *testing.T) func() {
+	dir := fs.NewDir(t, t.Name())
+	previous := config.Dir()
+	config.SetDir(dir.Path())
+	return func() {
+		config.SetDir(previous)
+		dir.Remove()
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string, files map[string]string) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gz.Close())
+	assert.NilError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NilError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, zw.Close())
+	assert.NilError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestInstallPluginBinary(t *testing.T) {
+	defer setTestConfigDir(t)()
+	src := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", testPluginScript, fs.WithMode(0755)))
+	defer src.Remove()
+
+	p, err := InstallPlugin(src.Join("docker-test"), InstallOptions{}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Name, "test"))
+	assert.Check(t, is.Equal(p.Vendor, "e2e-testing"))
+	assert.Check(t, is.Equal(p.Path, filepath.Join(config.UserPluginDir(), "docker-test")))
+	fi, err := os.Stat(p.Path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0755)))
+
+	_, err = InstallPlugin(src.Join("docker-test"), InstallOptions{}, nil)
+	assert.ErrorContains(t, err, `plugin "test" is already installed`)
+	_, err = InstallPlugin(src.Join("docker-test"), InstallOptions{Force: true}, nil)
+	assert.NilError(t, err)
+
+	p, err = InstallPlugin(src.Join("docker-test"), InstallOptions{Name: "renamed"}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Path, filepath.Join(config.UserPluginDir(), "docker-renamed")))
+
+	// the temporary directories are removed
+	entries, err := ioutil.ReadDir(config.UserPluginDir())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 2))
+}
+
+func TestInstallPluginArchive(t *testing.T) {
+	defer setTestConfigDir(t)()
+	src := fs.NewDir(t, t.Name())
+	defer src.Remove()
+	writeTestTarGz(t, src.Join("single.tgz"), map[string]string{
+		"README.md":          "readme",
+		"plugin/docker-test": testPluginScript,
+	})
+	writeTestTarGz(t, src.Join("multiple.tgz"), map[string]string{
+		"docker-one": testPluginScript,
+		"docker-two": testPluginScript,
+	})
+	writeTestZip(t, src.Join("single.zip"), map[string]string{
+		"docker-zipped": testPluginScript,
+	})
+
+	p, err := InstallPlugin(src.Join("single.tgz"), InstallOptions{}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Name, "test"))
+
+	p, err = InstallPlugin(src.Join("single.zip"), InstallOptions{}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Name, "zipped"))
+
+	_, err = InstallPlugin(src.Join("multiple.tgz"), InstallOptions{}, nil)
+	assert.ErrorContains(t, err, "holds several plugins (one, two)")
+	p, err = InstallPlugin(src.Join("multiple.tgz"), InstallOptions{Name: "two"}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Name, "two"))
+	_, err = InstallPlugin(src.Join("multiple.tgz"), InstallOptions{Name: "three"}, nil)
+	assert.ErrorContains(t, err, `does not hold plugin "three"`)
+}
+
+func TestInstallPluginChecksum(t *testing.T) {
+	defer setTestConfigDir(t)()
+	src := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", testPluginScript, fs.WithMode(0755)))
+	defer src.Remove()
+
+	_, err := InstallPlugin(src.Join("docker-test"), InstallOptions{SHA256: "0123"}, nil)
+	assert.ErrorContains(t, err, "checksum mismatch")
+	_, err = os.Stat(filepath.Join(config.UserPluginDir(), "docker-test"))
+	assert.Check(t, os.IsNotExist(err))
+
+	sum := sha256.Sum256([]byte(testPluginScript))
+	_, err = InstallPlugin(src.Join("docker-test"), InstallOptions{SHA256: hex.EncodeToString(sum[:])}, nil)
+	assert.NilError(t, err)
+}
+
+func TestInstallPluginInvalid(t *testing.T) {
+	defer setTestConfigDir(t)()
+	src := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-nometa", "#!/bin/sh\necho nope\n", fs.WithMode(0755)),
+		fs.WithFile("plugin", testPluginScript, fs.WithMode(0755)),
+	)
+	defer src.Remove()
+
+	_, err := InstallPlugin(src.Join("docker-nometa"), InstallOptions{}, nil)
+	assert.ErrorContains(t, err, "is not a valid CLI plugin: invalid metadata")
+	_, err = os.Stat(filepath.Join(config.UserPluginDir(), "docker-nometa"))
+	assert.Check(t, os.IsNotExist(err))
+
+	_, err = InstallPlugin(src.Join("plugin"), InstallOptions{}, nil)
+	assert.ErrorContains(t, err, "unable to determine the name of the plugin")
+	_, err = InstallPlugin(src.Join("plugin"), InstallOptions{Name: "../test"}, nil)
+	assert.ErrorContains(t, err, `plugin name "../test" did not match`)
+}
+
+func TestGetAndRemovePlugin(t *testing.T) {
+	defer setTestConfigDir(t)()
+	extra := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", testPluginScript, fs.WithMode(0755)))
+	defer extra.Remove()
+	src := fs.NewDir(t, t.Name(), fs.WithFile("docker-test", testPluginScript, fs.WithMode(0755)))
+	defer src.Remove()
+	_, err := InstallPlugin(src.Join("docker-test"), InstallOptions{}, nil)
+	assert.NilError(t, err)
+
+	cli := test.NewFakeCli(nil)
+	cli.ConfigFile().CLIPluginsExtraDirs = []string{extra.Path()}
+
+	p, err := GetPlugin(cli, "test", nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.Path, extra.Join("docker-test")))
+	assert.Check(t, is.DeepEqual(p.ShadowedPaths, []string{filepath.Join(config.UserPluginDir(), "docker-test")}))
+
+	path, err := RemovePlugin(cli, "test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, extra.Join("docker-test")))
+	path, err = RemovePlugin(cli, "test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, filepath.Join(config.UserPluginDir(), "docker-test")))
+
+	_, err = GetPlugin(cli, "test", nil)
+	assert.Check(t, IsNotFound(err))
+	_, err = RemovePlugin(cli, "test")
+	assert.Check(t, IsNotFound(err))
+}
+
+func TestSystemPluginDirOf(t *testing.T) {
+	systemDir := config.SystemPluginDirs()[0]
+	dir, ok := systemPluginDirOf(filepath.Join(systemDir, "docker-test"))
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(dir, systemDir))
+	_, ok = systemPluginDirOf(filepath.Join(config.UserPluginDir(), "docker-test"))
+	assert.Check(t, !ok)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/connhelper"
 	"github.com/spf13/cobra"
 )
 
@@ -30,14 +31,7 @@ func IsNotFound(err error) bool {
 }
 
 func getPluginDirs(dockerCli command.Cli) []string {
-	var pluginDirs []string
-
-	if cfg := dockerCli.ConfigFile(); cfg != nil {
-		pluginDirs = append(pluginDirs, cfg.CLIPluginsExtraDirs...)
-	}
-	pluginDirs = append(pluginDirs, config.Path("cli-plugins"))
-	pluginDirs = append(pluginDirs, defaultSystemPluginDirs...)
-	return pluginDirs
+	return config.PluginDirs(dockerCli.ConfigFile())
 }
 
 func addPluginCandidatesFromDir(res map[string][]string, d string) error {
@@ -54,7 +48,8 @@ func addPluginCandidatesFromDir(res map[string][]string, d string) error {
 			continue
 		}
 		name := dentry.Name()
-		if !strings.HasPrefix(name, NamePrefix) {
+		if !strings.HasPrefix(name, NamePrefix) || strings.HasPrefix(name, connhelper.PluginNamePrefix) {
+			// connection helper plugins share the plugin directories
 			continue
 		}
 		name = strings.TrimPrefix(name, NamePrefix)
@@ -88,9 +83,33 @@ func listPluginCandidates(dirs []string) (map[string][]string, error) {
 	return result, nil
 }
 
-// ListPlugins produces a list of the plugins available on the system
+// ListPlugins produces a list of the plugins available on the system. The
+// metadata of the plugins is cached, unless NoCacheEnvVar is set.
 func ListPlugins(dockerCli command.Cli, rootcmd *cobra.Command) ([]Plugin, error) {
-	candidates, err := listPluginCandidates(getPluginDirs(dockerCli))
+	return ListPluginsInDirs(getPluginDirs(dockerCli), rootcmd)
+}
+
+// ListPluginsInDirs produces a list of the plugins available in dirs, like
+// ListPlugins. It can be used before the CLI is initialized, when the plugin
+// directories of its configuration file are not known to it yet.
+func ListPluginsInDirs(dirs []string, rootcmd *cobra.Command) ([]Plugin, error) {
+	cache := loadMetadataCache()
+	defer cache.save()
+	return listPlugins(dirs, rootcmd, cache)
+}
+
+// RefreshPlugins produces a list of the plugins available on the system like
+// ListPlugins, but runs every plugin to fetch its metadata, and replaces the
+// cached metadata.
+func RefreshPlugins(dockerCli command.Cli, rootcmd *cobra.Command) ([]Plugin, error) {
+	cache := loadMetadataCache()
+	cache.reset()
+	defer cache.save()
+	return listPlugins(getPluginDirs(dockerCli), rootcmd, cache)
+}
+
+func listPlugins(dirs []string, rootcmd *cobra.Command, cache *metadataCache) ([]Plugin, error) {
+	candidates, err := listPluginCandidates(dirs)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +119,7 @@ func ListPlugins(dockerCli command.Cli, rootcmd *cobra.Command) ([]Plugin, error
 		if len(paths) == 0 {
 			continue
 		}
-		c := &candidate{paths[0]}
+		c := cache.candidate(&candidate{paths[0]})
 		p, err := newPlugin(c, rootcmd)
 		if err != nil {
 			return nil, err
@@ -126,6 +145,8 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 		return nil, errPluginNotFound(name)
 	}
 	exename := addExeSuffix(NamePrefix + name)
+	cache := loadMetadataCache()
+	defer cache.save()
 	for _, d := range getPluginDirs(dockerCli) {
 		path := filepath.Join(d, exename)
 
@@ -137,7 +158,7 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 			continue
 		}
 
-		c := &candidate{path: path}
+		c := cache.candidate(&candidate{path: path})
 		plugin, err := newPlugin(c, rootcmd)
 		if err != nil {
 			return nil, err
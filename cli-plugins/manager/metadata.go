@@ -22,4 +22,28 @@ type Metadata struct {
 	ShortDescription string `json:",omitempty"`
 	// URL is a pointer to the plugin's homepage.
 	URL string `json:",omitempty"`
+	// EndpointTypes are the optional types of context endpoints the plugin
+	// connects to. Contexts can be given an endpoint of each of these types.
+	EndpointTypes []EndpointType `json:",omitempty"`
+}
+
+// EndpointType describes a type of context endpoint declared by a plugin.
+type EndpointType struct {
+	// Name of the endpoint type, which is also the name of the flag setting
+	// it with `docker context create` and `docker context update`.
+	// Mandatory, must not be a builtin endpoint type.
+	Name string `json:",omitempty"`
+	// Description should be suitable for a single line help message.
+	Description string `json:",omitempty"`
+	// Keys are the configuration keys of the endpoint. Mandatory.
+	Keys []EndpointKey `json:",omitempty"`
+}
+
+// EndpointKey describes a configuration key of a context endpoint declared by
+// a plugin.
+type EndpointKey struct {
+	// Name of the key. Mandatory.
+	Name string `json:",omitempty"`
+	// Description should be suitable for a single line help message.
+	Description string `json:",omitempty"`
 }
@@ -6,12 +6,15 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/kubernetes"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 var (
-	pluginNameRe = regexp.MustCompile("^[a-z][a-z0-9]*$")
+	pluginNameRe       = regexp.MustCompile("^[a-z][a-z0-9]*$")
+	endpointTypeNameRe = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
 )
 
 // Plugin represents a potential plugin with all it's metadata.
@@ -103,5 +106,39 @@ func newPlugin(c Candidate, rootcmd *cobra.Command) (Plugin, error) {
 		p.Err = NewPluginError("plugin metadata does not define a vendor")
 		return p, nil
 	}
+	if err := validateEndpointTypes(p.Metadata.EndpointTypes); err != nil {
+		p.Err = err
+		return p, nil
+	}
 	return p, nil
 }
+
+func validateEndpointTypes(endpointTypes []EndpointType) error {
+	seen := map[string]bool{}
+	for _, t := range endpointTypes {
+		if !endpointTypeNameRe.MatchString(t.Name) {
+			return NewPluginError("plugin endpoint type %q did not match %q", t.Name, endpointTypeNameRe.String())
+		}
+		if t.Name == docker.DockerEndpoint || t.Name == kubernetes.KubernetesEndpoint {
+			return NewPluginError("plugin endpoint type %q duplicates a builtin endpoint type", t.Name)
+		}
+		if seen[t.Name] {
+			return NewPluginError("plugin endpoint type %q is declared more than once", t.Name)
+		}
+		seen[t.Name] = true
+		if len(t.Keys) == 0 {
+			return NewPluginError("plugin endpoint type %q does not define any key", t.Name)
+		}
+		seenKeys := map[string]bool{}
+		for _, k := range t.Keys {
+			if k.Name == "" || strings.ContainsAny(k.Name, "=,") {
+				return NewPluginError("plugin endpoint type %q has an invalid key %q", t.Name, k.Name)
+			}
+			if seenKeys[k.Name] {
+				return NewPluginError("plugin endpoint type %q declares key %q more than once", t.Name, k.Name)
+			}
+			seenKeys[k.Name] = true
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
 	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -124,3 +125,36 @@ func newMetadataSubcommand(plugin *cobra.Command, meta manager.Metadata) *cobra.
 	}
 	return cmd
 }
+
+// CurrentContextEndpoint returns the configuration of the endpoint of type
+// endpointType of the current context, as set with the `--<endpointType>`
+// flag of `docker context create` for a type declared in the `EndpointTypes`
+// of the plugin metadata. It returns nil if the current context has no such
+// endpoint. Must not be called before the CLI is initialized.
+func CurrentContextEndpoint(dockerCli command.Cli, endpointType string) (map[string]string, error) {
+	name := dockerCli.CurrentContext()
+	if name == "" {
+		return nil, nil
+	}
+	metadata, err := dockerCli.ContextStore().GetContextMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+	ep, ok := metadata.Endpoints[endpointType]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := ep.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("context %q: %s endpoint is not a plugin endpoint", name, endpointType)
+	}
+	config := make(map[string]string, len(values))
+	for key, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf("context %q: %s endpoint config key %q is not a string", name, endpointType, key)
+		}
+		config[key] = s
+	}
+	return config, nil
+}
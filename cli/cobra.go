@@ -13,6 +13,10 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// CommandAnnotationAlias is added to the stub commands of the aliases defined
+// in the config file. Its value is the expansion of the alias.
+const CommandAnnotationAlias = "com.docker.cli.alias"
+
 // setupCommonRootCommand contains the setup common to
 // SetupRootCommand and SetupPluginRootCommand.
 func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *pflag.FlagSet, *cobra.Command) {
@@ -25,9 +29,11 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *p
 	cobra.AddTemplateFunc("hasSubCommands", hasSubCommands)
 	cobra.AddTemplateFunc("hasManagementSubCommands", hasManagementSubCommands)
 	cobra.AddTemplateFunc("hasInvalidPlugins", hasInvalidPlugins)
+	cobra.AddTemplateFunc("hasAliasSubCommands", hasAliasSubCommands)
 	cobra.AddTemplateFunc("operationSubCommands", operationSubCommands)
 	cobra.AddTemplateFunc("managementSubCommands", managementSubCommands)
 	cobra.AddTemplateFunc("invalidPlugins", invalidPlugins)
+	cobra.AddTemplateFunc("aliasSubCommands", aliasSubCommands)
 	cobra.AddTemplateFunc("wrappedFlagUsages", wrappedFlagUsages)
 	cobra.AddTemplateFunc("commandVendor", commandVendor)
 	cobra.AddTemplateFunc("isFirstLevelCommand", isFirstLevelCommand) // is it an immediate sub-command of the root
@@ -122,6 +128,10 @@ func isPlugin(cmd *cobra.Command) bool {
 	return cmd.Annotations[pluginmanager.CommandAnnotationPlugin] == "true"
 }
 
+func isAlias(cmd *cobra.Command) bool {
+	return cmd.Annotations[CommandAnnotationAlias] != ""
+}
+
 func hasSubCommands(cmd *cobra.Command) bool {
 	return len(operationSubCommands(cmd)) > 0
 }
@@ -134,10 +144,14 @@ func hasInvalidPlugins(cmd *cobra.Command) bool {
 	return len(invalidPlugins(cmd)) > 0
 }
 
+func hasAliasSubCommands(cmd *cobra.Command) bool {
+	return len(aliasSubCommands(cmd)) > 0
+}
+
 func operationSubCommands(cmd *cobra.Command) []*cobra.Command {
 	cmds := []*cobra.Command{}
 	for _, sub := range cmd.Commands() {
-		if isPlugin(sub) && invalidPluginReason(sub) != "" {
+		if isPlugin(sub) && invalidPluginReason(sub) != "" || isAlias(sub) {
 			continue
 		}
 		if sub.IsAvailableCommand() && !sub.HasSubCommands() {
@@ -196,6 +210,16 @@ func invalidPlugins(cmd *cobra.Command) []*cobra.Command {
 	return cmds
 }
 
+func aliasSubCommands(cmd *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{}
+	for _, sub := range cmd.Commands() {
+		if isAlias(sub) {
+			cmds = append(cmds, sub)
+		}
+	}
+	return cmds
+}
+
 func invalidPluginReason(cmd *cobra.Command) string {
 	return cmd.Annotations[pluginmanager.CommandAnnotationPluginInvalid]
 }
@@ -243,6 +267,16 @@ Commands:
 {{- end}}
 {{- end}}
 
+{{- if hasAliasSubCommands . }}
+
+Command Aliases:
+
+{{- range aliasSubCommands . }}
+  {{rpad .Name .NamePadding }} {{.Short}}
+{{- end}}
+
+{{- end}}
+
 {{- if hasInvalidPlugins . }}
 
 Invalid Plugins:
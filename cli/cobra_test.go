@@ -76,3 +76,18 @@ func TestInvalidPlugin(t *testing.T) {
 
 	assert.DeepEqual(t, invalidPlugins(root), []*cobra.Command{sub1}, cmpopts.IgnoreUnexported(cobra.Command{}))
 }
+
+func TestAliasSubCommands(t *testing.T) {
+	run := func(_ *cobra.Command, _ []string) {}
+	root := &cobra.Command{Use: "root"}
+	sub := &cobra.Command{Use: "sub", Run: run}
+	alias := &cobra.Command{Use: "alias", Run: run, Annotations: map[string]string{CommandAnnotationAlias: "sub --foo"}}
+
+	assert.Assert(t, !hasAliasSubCommands(root))
+
+	root.AddCommand(sub, alias)
+	assert.Assert(t, is.Len(aliasSubCommands(root), 1))
+	assert.Equal(t, aliasSubCommands(root)[0], alias)
+	assert.Assert(t, is.Len(operationSubCommands(root), 1))
+	assert.Equal(t, operationSubCommands(root)[0], sub)
+}
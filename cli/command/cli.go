@@ -3,14 +3,17 @@ package command
 import (
 	"context"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/docker/cli/cli/config"
 	cliconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper"
 	dcontext "github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	kubcontext "github.com/docker/cli/cli/context/kubernetes"
@@ -189,13 +192,20 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions) error {
 	}
 
 	cli.configFile = cliconfig.LoadDefaultConfigFile(cli.err)
-	var err error
-	cli.contextStore = store.New(cliconfig.ContextStoreDir(), cli.contextStoreConfig)
+	storeOpts, err := contextStoreOptions(cli.configFile, cli.In(), cli.Err())
+	if err != nil {
+		return err
+	}
+	cli.contextStore = store.New(cliconfig.ContextStoreDir(), cli.contextStoreConfig, storeOpts...)
 	cli.currentContext, err = resolveContextName(opts.Common, cli.configFile, cli.contextStore)
 	if err != nil {
 		return err
 	}
-	endpoint, err := resolveDockerEndpoint(cli.contextStore, cli.currentContext, opts.Common)
+	cli.configFile, err = ConfigFileWithContextOverrides(cli.configFile, cli.contextStore, cli.currentContext)
+	if err != nil {
+		return err
+	}
+	endpoint, err := resolveDockerEndpoint(cli.contextStore, cli.currentContext, opts.Common, cli.configFile)
 	if err != nil {
 		return errors.Wrap(err, "unable to resolve docker endpoint")
 	}
@@ -232,18 +242,42 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions) error {
 
 // NewAPIClientFromFlags creates a new APIClient from command line flags
 func NewAPIClientFromFlags(opts *cliflags.CommonOptions, configFile *configfile.ConfigFile) (client.APIClient, error) {
-	store := store.New(cliconfig.ContextStoreDir(), defaultContextStoreConfig())
+	storeOpts, err := contextStoreOptions(configFile, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	store := store.New(cliconfig.ContextStoreDir(), defaultContextStoreConfig(), storeOpts...)
 	contextName, err := resolveContextName(opts, configFile, store)
 	if err != nil {
 		return nil, err
 	}
-	endpoint, err := resolveDockerEndpoint(store, contextName, opts)
+	configFile, err = ConfigFileWithContextOverrides(configFile, store, contextName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := resolveDockerEndpoint(store, contextName, opts, configFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to resolve docker endpoint")
 	}
 	return newAPIClientFromEndpoint(endpoint, configFile)
 }
 
+// NewAPIClientForContext creates a new APIClient for the docker endpoint of
+// the context contextName, for the commands which talk to several engines.
+// The configuration overrides of the context are applied.
+func NewAPIClientForContext(dockerCli Cli, contextName string) (client.APIClient, error) {
+	s := dockerCli.ContextStore()
+	configFile, err := ConfigFileWithContextOverrides(dockerCli.ConfigFile(), s, contextName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := resolveDockerEndpoint(s, contextName, &cliflags.CommonOptions{}, configFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to resolve docker endpoint of context %q", contextName)
+	}
+	return newAPIClientFromEndpoint(endpoint, configFile)
+}
+
 func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile) (client.APIClient, error) {
 	clientOpts, err := ep.ClientOpts()
 	if err != nil {
@@ -258,7 +292,8 @@ func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigF
 	return client.NewClientWithOpts(clientOpts...)
 }
 
-func resolveDockerEndpoint(s store.Store, contextName string, opts *cliflags.CommonOptions) (docker.Endpoint, error) {
+func resolveDockerEndpoint(s store.Store, contextName string, opts *cliflags.CommonOptions, configFile *configfile.ConfigFile) (docker.Endpoint, error) {
+	pluginDirs := cliconfig.PluginDirs(configFile)
 	if contextName != "" {
 		ctxMeta, err := s.GetContextMetadata(contextName)
 		if err != nil {
@@ -268,9 +303,14 @@ func resolveDockerEndpoint(s store.Store, contextName string, opts *cliflags.Com
 		if err != nil {
 			return docker.Endpoint{}, err
 		}
-		return docker.WithTLSData(s, contextName, epMeta)
+		ep, err := docker.WithTLSData(s, contextName, epMeta)
+		if err != nil {
+			return docker.Endpoint{}, err
+		}
+		ep.PluginDirs = pluginDirs
+		return ep, nil
 	}
-	host, err := getServerHost(opts.Hosts, opts.TLSOptions)
+	host, err := getServerHost(opts.Hosts, opts.TLSOptions, pluginDirs)
 	if err != nil {
 		return docker.Endpoint{}, err
 	}
@@ -293,7 +333,8 @@ func resolveDockerEndpoint(s store.Store, contextName string, opts *cliflags.Com
 			Host:          host,
 			SkipTLSVerify: skipTLSVerify,
 		},
-		TLSData: tlsData,
+		TLSData:    tlsData,
+		PluginDirs: pluginDirs,
 	}, nil
 }
 
@@ -456,7 +497,7 @@ func NewDockerCli(ops ...DockerCliOption) (*DockerCli, error) {
 	return cli, nil
 }
 
-func getServerHost(hosts []string, tlsOptions *tlsconfig.Options) (string, error) {
+func getServerHost(hosts []string, tlsOptions *tlsconfig.Options, pluginDirs []string) (string, error) {
 	var host string
 	switch len(hosts) {
 	case 0:
@@ -467,6 +508,14 @@ func getServerHost(hosts []string, tlsOptions *tlsconfig.Options) (string, error
 		return "", errors.New("Please specify only one -H")
 	}
 
+	// hosts handled by a connection helper plugin are passed as is to the
+	// plugin
+	if u, err := url.Parse(host); err == nil && strings.Contains(host, "://") {
+		if _, err := connhelper.LookupPlugin(u.Scheme, pluginDirs); err == nil {
+			return host, nil
+		}
+	}
+
 	return dopts.ParseHost(tlsOptions != nil, host)
 }
 
@@ -0,0 +1,125 @@
+package cliplugin
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultCLIPluginTableFormat = "table {{.Name}}\t{{.Vendor}}\t{{.Version}}\t{{.Status}}\t{{.Path}}\t{{.Error}}"
+
+	vendorHeader  = "VENDOR"
+	versionHeader = "VERSION"
+	pathHeader    = "PATH"
+	errorHeader   = "ERROR"
+)
+
+// Status of a CLI plugin binary
+const (
+	StatusValid    = "valid"
+	StatusInvalid  = "invalid"
+	StatusShadowed = "shadowed"
+)
+
+// NewFormat returns a Format for rendering using a CLI plugin Context
+func NewFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Name}}`
+		}
+		return defaultCLIPluginTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\nvendor: {{.Vendor}}\nversion: {{.Version}}\nstatus: {{.Status}}\npath: {{.Path}}\nerror: {{.Error}}\n`
+	}
+	return formatter.Format(source)
+}
+
+// FormatWrite writes the plugins, each followed by the plugins it shadows
+func FormatWrite(ctx formatter.Context, plugins []manager.Plugin) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, p := range plugins {
+			if err := format(&cliPluginContext{trunc: ctx.Trunc, p: p, path: p.Path}); err != nil {
+				return err
+			}
+			for _, path := range p.ShadowedPaths {
+				if err := format(&cliPluginContext{trunc: ctx.Trunc, p: manager.Plugin{Name: p.Name}, path: path, shadowed: true}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	pluginCtx := cliPluginContext{}
+	pluginCtx.Header = formatter.SubHeaderContext{
+		"Name":             formatter.NameHeader,
+		"Vendor":           vendorHeader,
+		"Version":          versionHeader,
+		"ShortDescription": formatter.DescriptionHeader,
+		"Status":           formatter.StatusHeader,
+		"Path":             pathHeader,
+		"Error":            errorHeader,
+	}
+	return ctx.Write(&pluginCtx, render)
+}
+
+type cliPluginContext struct {
+	formatter.HeaderContext
+	trunc    bool
+	p        manager.Plugin
+	path     string
+	shadowed bool
+}
+
+func (c *cliPluginContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *cliPluginContext) Name() string {
+	return c.p.Name
+}
+
+func (c *cliPluginContext) Vendor() string {
+	return c.p.Vendor
+}
+
+func (c *cliPluginContext) Version() string {
+	return c.p.Version
+}
+
+func (c *cliPluginContext) ShortDescription() string {
+	if c.trunc {
+		return formatter.Ellipsis(c.p.ShortDescription, 45)
+	}
+	return c.p.ShortDescription
+}
+
+func (c *cliPluginContext) Status() string {
+	switch {
+	case c.shadowed:
+		return StatusShadowed
+	case c.p.Err != nil:
+		return StatusInvalid
+	}
+	return StatusValid
+}
+
+func (c *cliPluginContext) Path() string {
+	return c.path
+}
+
+func (c *cliPluginContext) Error() string {
+	if c.p.Err == nil {
+		return ""
+	}
+	msg := strings.Replace(c.p.Err.Error(), "\n", " ", -1)
+	if c.trunc {
+		return formatter.Ellipsis(msg, 60)
+	}
+	return msg
+}
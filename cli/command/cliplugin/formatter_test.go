@@ -0,0 +1,54 @@
+package cliplugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command/formatter"
+	"gotest.tools/assert"
+	"gotest.tools/golden"
+)
+
+func testPlugins() []manager.Plugin {
+	return []manager.Plugin{
+		{
+			Metadata:      manager.Metadata{Vendor: "Docker Inc.", Version: "v1.0"},
+			Name:          "app",
+			Path:          "/home/user/.docker/cli-plugins/docker-app",
+			ShadowedPaths: []string{"/usr/libexec/docker/cli-plugins/docker-app"},
+		},
+		{
+			Name: "broken",
+			Path: "/home/user/.docker/cli-plugins/docker-broken",
+			Err:  manager.NewPluginError("plugin metadata does not define a vendor"),
+		},
+	}
+}
+
+func TestCLIPluginContextWrite(t *testing.T) {
+	cases := []struct {
+		name   string
+		format string
+		trunc  bool
+	}{
+		{name: "table", format: "table", trunc: true},
+		{name: "raw", format: "raw"},
+		{name: "template", format: "{{.Name}} {{.Status}}"},
+		{name: "json", format: "{{json .}}"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ctx := formatter.Context{
+				Output: &out,
+				Format: NewFormat(tc.format, false),
+				Trunc:  tc.trunc,
+			}
+			assert.NilError(t, FormatWrite(ctx, testPlugins()))
+			golden.Assert(t, out.String(), "list-"+tc.name+".golden")
+		})
+	}
+}
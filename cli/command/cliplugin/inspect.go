@@ -0,0 +1,40 @@
+package cliplugin
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/inspect"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	names  []string
+	format string
+}
+
+func newInspectCommand(dockerCli command.Cli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] PLUGIN [PLUGIN...]",
+		Short: "Display detailed information on one or more CLI plugins",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runInspect(dockerCli, cmd.Root(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
+	return cmd
+}
+
+func runInspect(dockerCli command.Cli, rootcmd *cobra.Command, opts inspectOptions) error {
+	getRef := func(name string) (interface{}, []byte, error) {
+		p, err := manager.GetPlugin(dockerCli, name, rootcmd)
+		return p, nil, err
+	}
+	return inspect.Inspect(dockerCli.Out(), opts.names, opts.format, getRef)
+}
@@ -0,0 +1,48 @@
+package cliplugin
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type installOptions struct {
+	source string
+	manager.InstallOptions
+}
+
+func newInstallCommand(dockerCli command.Cli) *cobra.Command {
+	var opts installOptions
+
+	cmd := &cobra.Command{
+		Use:   "install [OPTIONS] FILE|ARCHIVE",
+		Short: "Install a CLI plugin for the current user",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			return runInstall(dockerCli, cmd.Root(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Name, "name", "", "Name of the plugin, if it cannot be determined from the file")
+	flags.StringVar(&opts.SHA256, "sha256", "", "Expected SHA256 checksum of the file")
+	flags.BoolVarP(&opts.Force, "force", "f", false, "Replace the plugin if it is already installed")
+	return cmd
+}
+
+func runInstall(dockerCli command.Cli, rootcmd *cobra.Command, opts installOptions) error {
+	p, err := manager.InstallPlugin(opts.source, opts.InstallOptions, rootcmd)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), p.Name)
+	fmt.Fprintf(dockerCli.Err(), "Successfully installed CLI plugin %q in %s\n", p.Name, p.Path)
+	if active, err := manager.GetPlugin(dockerCli, p.Name, rootcmd); err == nil && active.Path != p.Path {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: CLI plugin %q is shadowed by %s\n", p.Name, active.Path)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cliplugin
+
+import (
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/spf13/cobra"
+	"vbom.ml/util/sortorder"
+)
+
+type listOptions struct {
+	quiet   bool
+	noTrunc bool
+	format  string
+	sort    string
+	noCache bool
+}
+
+func newListCommand(dockerCli command.Cli) *cobra.Command {
+	var options listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Short:   "List CLI plugins",
+		Aliases: []string{"list"},
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, cmd.Root(), options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display plugin names")
+	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&options.format, "format", "", "Pretty-print CLI plugins using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
+	flags.BoolVar(&options.noCache, "no-cache", false, "Run every plugin to get its metadata, and refresh the metadata cache")
+	return cmd
+}
+
+func runList(dockerCli command.Cli, rootcmd *cobra.Command, options listOptions) error {
+	listPlugins := manager.ListPlugins
+	if options.noCache {
+		listPlugins = manager.RefreshPlugins
+	}
+	plugins, err := listPlugins(dockerCli, rootcmd)
+	if err != nil {
+		return err
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return sortorder.NaturalLess(plugins[i].Name, plugins[j].Name)
+	})
+	if options.quiet {
+		// only the plugins which are run are listed
+		for i := range plugins {
+			plugins[i].ShadowedPaths = nil
+		}
+	}
+
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	pluginsCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
+		Trunc:  !options.noTrunc,
+	}
+	return FormatWrite(pluginsCtx, plugins)
+}
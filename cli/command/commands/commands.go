@@ -7,6 +7,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/builder"
 	"github.com/docker/cli/cli/command/checkpoint"
+	"github.com/docker/cli/cli/command/cliplugin"
 	"github.com/docker/cli/cli/command/config"
 	"github.com/docker/cli/cli/command/container"
 	"github.com/docker/cli/cli/command/context"
@@ -33,6 +34,9 @@ func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 		// checkpoint
 		checkpoint.NewCheckpointCommand(dockerCli),
 
+		// cli-plugin
+		cliplugin.NewCLIPluginCommand(dockerCli),
+
 		// config
 		config.NewConfigCommand(dockerCli),
 
@@ -16,6 +16,7 @@ import (
 type listOptions struct {
 	quiet  bool
 	format string
+	sort   string
 	filter opts.FilterOpt
 }
 
@@ -35,6 +36,7 @@ func newConfigListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&listOpts.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVarP(&listOpts.format, "format", "", "", "Pretty-print configs using a Go template")
+	formatter.AddSortFlag(flags, &listOpts.sort)
 	flags.VarP(&listOpts.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -65,6 +67,7 @@ func runConfigList(dockerCli command.Cli, options listOptions) error {
 	configCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
 	}
 	return FormatWrite(configCtx, configs)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 )
@@ -21,10 +22,12 @@ type fakeClient struct {
 	infoFunc              func() (types.Info, error)
 	containerStatPathFunc func(container, path string) (types.ContainerPathStat, error)
 	containerCopyFromFunc func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
+	containerCopyToFunc   func(container, path string, content io.Reader, options types.CopyToContainerOptions) error
 	logFunc               func(string, types.ContainerLogsOptions) (io.ReadCloser, error)
 	waitFunc              func(string) (<-chan container.ContainerWaitOKBody, <-chan error)
 	containerListFunc     func(types.ContainerListOptions) ([]types.Container, error)
 	containerExportFunc   func(string) (io.ReadCloser, error)
+	eventsFunc            func(types.EventsOptions) (<-chan events.Message, <-chan error)
 	Version               string
 }
 
@@ -101,6 +104,13 @@ func (f *fakeClient) CopyFromContainer(_ context.Context, container, srcPath str
 	return nil, types.ContainerPathStat{}, nil
 }
 
+func (f *fakeClient) CopyToContainer(_ context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	if f.containerCopyToFunc != nil {
+		return f.containerCopyToFunc(container, path, content, options)
+	}
+	return nil
+}
+
 func (f *fakeClient) ContainerLogs(_ context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
 	if f.logFunc != nil {
 		return f.logFunc(container, options)
@@ -132,3 +142,10 @@ func (f *fakeClient) ContainerExport(_ context.Context, container string) (io.Re
 	}
 	return nil, nil
 }
+
+func (f *fakeClient) Events(_ context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(options)
+	}
+	return nil, nil
+}
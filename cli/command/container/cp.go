@@ -9,7 +9,9 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types"
+	apiclient "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
@@ -17,10 +19,14 @@ import (
 )
 
 type copyOptions struct {
-	source      string
-	destination string
-	followLink  bool
-	copyUIDGID  bool
+	source             string
+	destination        string
+	sourceContext      string
+	destinationContext string
+	followLink         bool
+	copyUIDGID         bool
+	sync               bool
+	quiet              bool
 }
 
 type copyDirection int
@@ -34,9 +40,22 @@ const (
 type cpConfig struct {
 	followLink bool
 	copyUIDGID bool
+	sync       bool
 	sourcePath string
 	destPath   string
 	container  string
+	// client is used to copy from or to container. When copying between
+	// containers, it is the client of the source container.
+	client apiclient.APIClient
+
+	// the destination container, and its client, when copying between
+	// containers
+	destContainer string
+	destClient    apiclient.APIClient
+
+	// progressOut is where the progress of the copy is displayed, if it is
+	// not nil
+	progressOut io.Writer
 }
 
 // NewCopyCommand creates a new `docker cp` command
@@ -45,10 +64,12 @@ func NewCopyCommand(dockerCli command.Cli) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use: `cp [OPTIONS] CONTAINER:SRC_PATH DEST_PATH|-
-	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH`,
+	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH
+	docker cp [OPTIONS] CONTAINER:SRC_PATH CONTAINER:DEST_PATH`,
 		Short: "Copy files/folders between a container and the local filesystem",
 		Long: strings.Join([]string{
-			"Copy files/folders between a container and the local filesystem\n",
+			"Copy files/folders between a container and the local filesystem,\n",
+			"or between two containers\n",
 			"\nUse '-' as the source to read a tar archive from stdin\n",
 			"and extract it to a directory destination in a container.\n",
 			"Use '-' as the destination to stream a tar archive of a\n",
@@ -71,6 +92,10 @@ func NewCopyCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
+	flags.StringVar(&opts.sourceContext, "source-context", "", "Context of the source container (default: the current context)")
+	flags.StringVar(&opts.destinationContext, "destination-context", "", "Context of the destination container (default: the current context)")
+	flags.BoolVar(&opts.sync, "sync", false, "Skip the files whose size and modification time match the destination")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output, which is only displayed on a terminal")
 	return cmd
 }
 
@@ -78,12 +103,30 @@ func runCopy(dockerCli command.Cli, opts copyOptions) error {
 	srcContainer, srcPath := splitCpArg(opts.source)
 	destContainer, destPath := splitCpArg(opts.destination)
 
+	if opts.sourceContext != "" && srcContainer == "" {
+		return errors.New("--source-context can only be used when copying from a container")
+	}
+	if opts.destinationContext != "" && destContainer == "" {
+		return errors.New("--destination-context can only be used when copying to a container")
+	}
+
+	if opts.sync && (srcPath == "-" || destPath == "-") {
+		return errors.New("--sync cannot be used to copy a tar archive from stdin or to stdout")
+	}
+
 	copyConfig := cpConfig{
 		followLink: opts.followLink,
 		copyUIDGID: opts.copyUIDGID,
+		sync:       opts.sync,
 		sourcePath: srcPath,
 		destPath:   destPath,
 	}
+	// the progress is written to stderr, as stdout may be the copied archive
+	if errOut := streams.NewOut(dockerCli.Err()); !opts.quiet && errOut.IsTerminal() {
+		copyConfig.progressOut = errOut
+	}
+
+	ctx := context.Background()
 
 	var direction copyDirection
 	if srcContainer != "" {
@@ -94,21 +137,59 @@ func runCopy(dockerCli command.Cli, opts copyOptions) error {
 		direction |= toContainer
 		copyConfig.container = destContainer
 	}
-
-	ctx := context.Background()
+	srcClient, err := copyClient(ctx, dockerCli, opts.sourceContext)
+	if err != nil {
+		return err
+	}
+	defer srcClient.Close()
+	destClient, err := copyClient(ctx, dockerCli, opts.destinationContext)
+	if err != nil {
+		return err
+	}
+	defer destClient.Close()
 
 	switch direction {
 	case fromContainer:
+		copyConfig.client = srcClient
 		return copyFromContainer(ctx, dockerCli, copyConfig)
 	case toContainer:
-		return copyToContainer(ctx, dockerCli, copyConfig)
+		copyConfig.client = destClient
+		return copyToContainer(ctx, copyConfig)
 	case acrossContainers:
-		return errors.New("copying between containers is not supported")
+		copyConfig.container = srcContainer
+		copyConfig.client = srcClient
+		copyConfig.destContainer = destContainer
+		copyConfig.destClient = destClient
+		return copyAcrossContainers(ctx, copyConfig)
 	default:
 		return errors.New("must specify at least one container source")
 	}
 }
 
+// copyClient returns the client to use for the container of the context
+// contextName, which is the client of the current context if empty
+func copyClient(ctx context.Context, dockerCli command.Cli, contextName string) (apiclient.APIClient, error) {
+	if contextName == "" {
+		return nopCloseClient{dockerCli.Client()}, nil
+	}
+	client, err := command.NewAPIClientForContext(dockerCli, contextName)
+	if err != nil {
+		return nil, err
+	}
+	client.NegotiateAPIVersion(ctx)
+	return client, nil
+}
+
+// nopCloseClient is a client which is not closed by the copy, as it is shared
+// with the rest of the CLI
+type nopCloseClient struct {
+	apiclient.APIClient
+}
+
+func (nopCloseClient) Close() error {
+	return nil
+}
+
 func resolveLocalPath(localPath string) (absPath string, err error) {
 	if absPath, err = filepath.Abs(localPath); err != nil {
 		return
@@ -128,26 +209,9 @@ func copyFromContainer(ctx context.Context, dockerCli command.Cli, copyConfig cp
 		}
 	}
 
-	client := dockerCli.Client()
+	client := copyConfig.client
 	// if client requests to follow symbol link, then must decide target file to be copied
-	var rebaseName string
-	if copyConfig.followLink {
-		srcStat, err := client.ContainerStatPath(ctx, copyConfig.container, srcPath)
-
-		// If the destination is a symbolic link, we should follow it.
-		if err == nil && srcStat.Mode&os.ModeSymlink != 0 {
-			linkTarget := srcStat.LinkTarget
-			if !system.IsAbs(linkTarget) {
-				// Join with the parent directory.
-				srcParent, _ := archive.SplitPathDirEntry(srcPath)
-				linkTarget = filepath.Join(srcParent, linkTarget)
-			}
-
-			linkTarget, rebaseName = archive.GetRebaseName(srcPath, linkTarget)
-			srcPath = linkTarget
-		}
-
-	}
+	srcPath, rebaseName := resolveContainerSource(ctx, client, copyConfig.container, srcPath, copyConfig.followLink)
 
 	content, stat, err := client.CopyFromContainer(ctx, copyConfig.container, srcPath)
 	if err != nil {
@@ -167,19 +231,95 @@ func copyFromContainer(ctx context.Context, dockerCli command.Cli, copyConfig cp
 		RebaseName: rebaseName,
 	}
 
-	preArchive := content
-	if len(srcInfo.RebaseName) != 0 {
-		_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
-		preArchive = archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
+	if !copyConfig.sync && copyConfig.progressOut == nil {
+		return archive.CopyTo(rebaseArchive(content, srcInfo), srcInfo, dstPath)
 	}
-	return archive.CopyTo(preArchive, srcInfo, dstPath)
+
+	// The destination path need not exist, but CopyInfoDestinationPath will
+	// ensure that at least the parent directory exists. The path is normalized
+	// the same way as archive.CopyTo does.
+	dstInfo, err := archive.CopyInfoDestinationPath(filepath.FromSlash(dstPath))
+	if err != nil {
+		return err
+	}
+	dstDir, preparedArchive, err := archive.PrepareArchiveCopy(rebaseArchive(content, srcInfo), srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	defer preparedArchive.Close()
+
+	var upToDate upToDateFunc
+	if copyConfig.sync {
+		upToDate = localUpToDate(dstDir)
+	}
+	progress := copyConfig.newProgress(func() int64 { return containerCopySize(stat) })
+	progress.Start()
+	defer progress.Stop()
+	copyArchive := processArchive(preparedArchive, upToDate, progress)
+	defer copyArchive.Close()
+
+	options := &archive.TarOptions{
+		NoLchown:             true,
+		NoOverwriteDirNonDir: true,
+	}
+	return archive.Untar(copyArchive, dstDir, options)
+}
+
+// newProgress returns the progress of the copy, whose size is computed by
+// total, or nil if the progress is not displayed
+func (c cpConfig) newProgress(total func() int64) *copyProgress {
+	if c.progressOut == nil {
+		return nil
+	}
+	return newCopyProgress(c.progressOut, total())
+}
+
+// containerCopySize returns the size of the copy of a source of the
+// container, which is only known for a file
+func containerCopySize(stat types.ContainerPathStat) int64 {
+	if stat.Mode.IsDir() {
+		return 0
+	}
+	return stat.Size
+}
+
+// resolveContainerSource returns the path of the source to copy from the
+// container, which is the target of srcPath if it is a symbolic link to follow,
+// and the name the target must be renamed to in the archive
+func resolveContainerSource(ctx context.Context, client apiclient.APIClient, container, srcPath string, followLink bool) (string, string) {
+	if !followLink {
+		return srcPath, ""
+	}
+	srcStat, err := client.ContainerStatPath(ctx, container, srcPath)
+
+	// If the source is a symbolic link, we should follow it.
+	if err != nil || srcStat.Mode&os.ModeSymlink == 0 {
+		return srcPath, ""
+	}
+	linkTarget := srcStat.LinkTarget
+	if !system.IsAbs(linkTarget) {
+		// Join with the parent directory.
+		srcParent, _ := archive.SplitPathDirEntry(srcPath)
+		linkTarget = filepath.Join(srcParent, linkTarget)
+	}
+	return archive.GetRebaseName(srcPath, linkTarget)
+}
+
+// rebaseArchive renames the source in the archive of a followed symbolic link
+// to the name of the link
+func rebaseArchive(content io.ReadCloser, srcInfo archive.CopyInfo) io.ReadCloser {
+	if len(srcInfo.RebaseName) == 0 {
+		return content
+	}
+	_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
+	return archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
 }
 
 // In order to get the copy behavior right, we need to know information
 // about both the source and destination. The API is a simple tar
 // archive/extract API but we can use the stat info header about the
 // destination to be more informed about exactly what the destination is.
-func copyToContainer(ctx context.Context, dockerCli command.Cli, copyConfig cpConfig) (err error) {
+func copyToContainer(ctx context.Context, copyConfig cpConfig) (err error) {
 	srcPath := copyConfig.sourcePath
 	dstPath := copyConfig.destPath
 
@@ -191,33 +331,9 @@ func copyToContainer(ctx context.Context, dockerCli command.Cli, copyConfig cpCo
 		}
 	}
 
-	client := dockerCli.Client()
+	client := copyConfig.client
 	// Prepare destination copy info by stat-ing the container path.
-	dstInfo := archive.CopyInfo{Path: dstPath}
-	dstStat, err := client.ContainerStatPath(ctx, copyConfig.container, dstPath)
-
-	// If the destination is a symbolic link, we should evaluate it.
-	if err == nil && dstStat.Mode&os.ModeSymlink != 0 {
-		linkTarget := dstStat.LinkTarget
-		if !system.IsAbs(linkTarget) {
-			// Join with the parent directory.
-			dstParent, _ := archive.SplitPathDirEntry(dstPath)
-			linkTarget = filepath.Join(dstParent, linkTarget)
-		}
-
-		dstInfo.Path = linkTarget
-		dstStat, err = client.ContainerStatPath(ctx, copyConfig.container, linkTarget)
-	}
-
-	// Ignore any error and assume that the parent directory of the destination
-	// path exists, in which case the copy may still succeed. If there is any
-	// type of conflict (e.g., non-directory overwriting an existing directory
-	// or vice versa) the extraction will fail. If the destination simply did
-	// not exist, but the parent directory does, the extraction will still
-	// succeed.
-	if err == nil {
-		dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
-	}
+	dstInfo := statContainerDestination(ctx, client, copyConfig.container, dstPath)
 
 	var (
 		content         io.Reader
@@ -261,8 +377,18 @@ func copyToContainer(ctx context.Context, dockerCli command.Cli, copyConfig cpCo
 		}
 		defer preparedArchive.Close()
 
+		var upToDate upToDateFunc
+		if copyConfig.sync {
+			upToDate = containerUpToDate(ctx, client, copyConfig.container, dstDir)
+		}
+		progress := copyConfig.newProgress(func() int64 { return localCopySize(srcInfo.Path) })
+		progress.Start()
+		defer progress.Stop()
+		copyArchive := processArchive(preparedArchive, upToDate, progress)
+		defer copyArchive.Close()
+
 		resolvedDstPath = dstDir
-		content = preparedArchive
+		content = copyArchive
 	}
 
 	options := types.CopyToContainerOptions{
@@ -272,6 +398,80 @@ func copyToContainer(ctx context.Context, dockerCli command.Cli, copyConfig cpCo
 	return client.CopyToContainer(ctx, copyConfig.container, resolvedDstPath, content, options)
 }
 
+// statContainerDestination returns the copy info of the destination path in
+// the container, following it if it is a symbolic link
+func statContainerDestination(ctx context.Context, client apiclient.APIClient, container, dstPath string) archive.CopyInfo {
+	dstInfo := archive.CopyInfo{Path: dstPath}
+	dstStat, err := client.ContainerStatPath(ctx, container, dstPath)
+
+	// If the destination is a symbolic link, we should evaluate it.
+	if err == nil && dstStat.Mode&os.ModeSymlink != 0 {
+		linkTarget := dstStat.LinkTarget
+		if !system.IsAbs(linkTarget) {
+			// Join with the parent directory.
+			dstParent, _ := archive.SplitPathDirEntry(dstPath)
+			linkTarget = filepath.Join(dstParent, linkTarget)
+		}
+
+		dstInfo.Path = linkTarget
+		dstStat, err = client.ContainerStatPath(ctx, container, linkTarget)
+	}
+
+	// Ignore any error and assume that the parent directory of the destination
+	// path exists, in which case the copy may still succeed. If there is any
+	// type of conflict (e.g., non-directory overwriting an existing directory
+	// or vice versa) the extraction will fail. If the destination simply did
+	// not exist, but the parent directory does, the extraction will still
+	// succeed.
+	if err == nil {
+		dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
+	}
+	return dstInfo
+}
+
+// copyAcrossContainers streams the archive of the source container straight
+// into the destination container, which may be managed by another engine.
+// The source is resolved as when copying from a container, and the archive is
+// prepared as when copying to a container.
+func copyAcrossContainers(ctx context.Context, copyConfig cpConfig) error {
+	srcPath, rebaseName := resolveContainerSource(ctx, copyConfig.client, copyConfig.container, copyConfig.sourcePath, copyConfig.followLink)
+	dstInfo := statContainerDestination(ctx, copyConfig.destClient, copyConfig.destContainer, copyConfig.destPath)
+
+	content, stat, err := copyConfig.client.CopyFromContainer(ctx, copyConfig.container, srcPath)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	srcInfo := archive.CopyInfo{
+		Path:       srcPath,
+		Exists:     true,
+		IsDir:      stat.Mode.IsDir(),
+		RebaseName: rebaseName,
+	}
+	dstDir, preparedArchive, err := archive.PrepareArchiveCopy(rebaseArchive(content, srcInfo), srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	defer preparedArchive.Close()
+
+	var upToDate upToDateFunc
+	if copyConfig.sync {
+		upToDate = containerUpToDate(ctx, copyConfig.destClient, copyConfig.destContainer, dstDir)
+	}
+	progress := copyConfig.newProgress(func() int64 { return containerCopySize(stat) })
+	progress.Start()
+	defer progress.Stop()
+	copyArchive := processArchive(preparedArchive, upToDate, progress)
+	defer copyArchive.Close()
+
+	options := types.CopyToContainerOptions{
+		AllowOverwriteDirWithFile: false,
+		CopyUIDGID:                copyConfig.copyUIDGID,
+	}
+	return copyConfig.destClient.CopyToContainer(ctx, copyConfig.destContainer, dstDir, copyArchive, options)
+}
+
 // We use `:` as a delimiter between CONTAINER and PATH, but `:` could also be
 // in a valid LOCALPATH, like `file:name.txt`. We can resolve this ambiguity by
 // requiring a LOCALPATH with a `:` to be made explicit with a relative or
@@ -0,0 +1,221 @@
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apiclient "github.com/docker/docker/client"
+	units "github.com/docker/go-units"
+)
+
+const copyProgressInterval = 500 * time.Millisecond
+
+// copyProgress reports the progress of a copy on a terminal
+type copyProgress struct {
+	out   io.Writer
+	start time.Time
+	// total is the size of the files to copy, or 0 if it is not known
+	total int64
+
+	mu sync.Mutex
+	// bytes is the size of the files processed so far, including the
+	// skipped ones
+	bytes   int64
+	files   int
+	skipped int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCopyProgress(out io.Writer, total int64) *copyProgress {
+	return &copyProgress{out: out, total: total, start: time.Now(), stop: make(chan struct{})}
+}
+
+// Start refreshes the progress line until Stop is called
+func (p *copyProgress) Start() {
+	if p == nil {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(copyProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(p.out, "\r%s\033[K", p.String())
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop prints the final progress line
+func (p *copyProgress) Stop() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+	fmt.Fprintf(p.out, "\r%s\033[K\n", p.String())
+}
+
+func (p *copyProgress) addBytes(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.bytes += n
+	p.mu.Unlock()
+}
+
+func (p *copyProgress) addFile(skipped bool, size int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if skipped {
+		p.skipped++
+		p.bytes += size
+	} else {
+		p.files++
+	}
+	p.mu.Unlock()
+}
+
+func (p *copyProgress) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return formatCopyProgress(p.bytes, p.total, p.files, p.skipped, time.Since(p.start))
+}
+
+func formatCopyProgress(bytes, total int64, files, skipped int, elapsed time.Duration) string {
+	parts := []string{fmt.Sprintf("%d files copied", files)}
+	if skipped > 0 {
+		parts[0] += fmt.Sprintf(", %d up to date", skipped)
+	}
+	if total > 0 {
+		parts = append(parts, fmt.Sprintf("%s / %s", units.HumanSize(float64(bytes)), units.HumanSize(float64(total))))
+	} else {
+		parts = append(parts, units.HumanSize(float64(bytes)))
+	}
+	if elapsed >= time.Second {
+		rate := float64(bytes) / elapsed.Seconds()
+		parts = append(parts, units.HumanSize(rate)+"/s")
+		if total > bytes && rate > 0 {
+			eta := time.Duration(float64(total-bytes) / rate * float64(time.Second))
+			parts = append(parts, "ETA "+eta.Round(time.Second).String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// progressWriter counts the bytes written to a copy
+type progressWriter struct {
+	io.Writer
+	progress *copyProgress
+}
+
+func (w progressWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	w.progress.addBytes(int64(n))
+	return n, err
+}
+
+// upToDateFunc reports whether the regular file of the header is already
+// copied to the destination
+type upToDateFunc func(hdr *tar.Header) bool
+
+// processArchive returns a copy of the tar archive content, without the
+// regular files which are up to date, and which reports the progress of the
+// copy. The archive is returned as is if there is nothing to do.
+func processArchive(content io.Reader, upToDate upToDateFunc, progress *copyProgress) io.ReadCloser {
+	if upToDate == nil && progress == nil {
+		if rc, ok := content.(io.ReadCloser); ok {
+			return rc
+		}
+		return ioutil.NopCloser(content)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(copyArchiveEntries(pw, content, upToDate, progress))
+	}()
+	return pr
+}
+
+func copyArchiveEntries(dst io.Writer, src io.Reader, upToDate upToDateFunc, progress *copyProgress) error {
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if upToDate != nil && upToDate(hdr) {
+				progress.addFile(true, hdr.Size)
+				continue
+			}
+			progress.addFile(false, hdr.Size)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(progressWriter{Writer: tw, progress: progress}, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// sameFile reports whether a regular file of the destination has the size
+// and modification time of the file of the header. Modification times are
+// compared to the second, which is the precision of tar archives.
+func sameFile(hdr *tar.Header, size int64, mode os.FileMode, modTime time.Time) bool {
+	return mode.IsRegular() && size == hdr.Size && modTime.Truncate(time.Second).Equal(hdr.ModTime.Truncate(time.Second))
+}
+
+// localUpToDate compares the files of an archive extracted to dstDir with
+// the local files
+func localUpToDate(dstDir string) upToDateFunc {
+	return func(hdr *tar.Header) bool {
+		fi, err := os.Lstat(filepath.Join(dstDir, filepath.FromSlash(hdr.Name)))
+		return err == nil && sameFile(hdr, fi.Size(), fi.Mode(), fi.ModTime())
+	}
+}
+
+// containerUpToDate compares the files of an archive extracted to dstDir in
+// the container with the files of the container. The API has no way to list a
+// directory, so each file is looked up with its own ContainerStatPath request.
+func containerUpToDate(ctx context.Context, client apiclient.APIClient, container, dstDir string) upToDateFunc {
+	return func(hdr *tar.Header) bool {
+		stat, err := client.ContainerStatPath(ctx, container, path.Join(dstDir, hdr.Name))
+		return err == nil && sameFile(hdr, stat.Size, stat.Mode, stat.Mtime)
+	}
+}
+
+// localCopySize returns the size of the regular files under srcPath
+func localCopySize(srcPath string) int64 {
+	var total int64
+	filepath.Walk(srcPath, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			total += fi.Size()
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,156 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestFormatCopyProgress(t *testing.T) {
+	var testcases = []struct {
+		doc      string
+		bytes    int64
+		total    int64
+		files    int
+		skipped  int
+		elapsed  time.Duration
+		expected string
+	}{
+		{
+			doc:      "just started",
+			bytes:    1000,
+			files:    1,
+			elapsed:  100 * time.Millisecond,
+			expected: "1 files copied, 1kB",
+		},
+		{
+			doc:      "unknown total",
+			bytes:    20000000,
+			files:    12,
+			elapsed:  2 * time.Second,
+			expected: "12 files copied, 20MB, 10MB/s",
+		},
+		{
+			doc:      "known total",
+			bytes:    20000000,
+			total:    50000000,
+			files:    12,
+			elapsed:  2 * time.Second,
+			expected: "12 files copied, 20MB / 50MB, 10MB/s, ETA 3s",
+		},
+		{
+			doc:      "skipped files",
+			bytes:    50000000,
+			total:    50000000,
+			files:    2,
+			skipped:  10,
+			elapsed:  5 * time.Second,
+			expected: "2 files copied, 10 up to date, 50MB / 50MB, 10MB/s",
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.doc, func(t *testing.T) {
+			actual := formatCopyProgress(testcase.bytes, testcase.total, testcase.files, testcase.skipped, testcase.elapsed)
+			assert.Check(t, is.Equal(testcase.expected, actual))
+		})
+	}
+}
+
+func TestProcessArchive(t *testing.T) {
+	mtime := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "dir/", Mode: 0755, Typeflag: tar.TypeDir, ModTime: mtime}))
+	for _, name := range []string{"dir/same", "dir/changed"} {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 7, Typeflag: tar.TypeReg, ModTime: mtime}))
+		_, err := tw.Write([]byte("content"))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	upToDate := func(hdr *tar.Header) bool {
+		return hdr.Name == "dir/same"
+	}
+	progress := newCopyProgress(ioutil.Discard, 14)
+	content := processArchive(&buf, upToDate, progress)
+	defer content.Close()
+
+	assert.Check(t, is.DeepEqual([]string{"dir/", "dir/changed"}, readTarEntries(t, content)))
+	assert.Check(t, is.Equal(int64(14), progress.bytes))
+	assert.Check(t, is.Equal(1, progress.files))
+	assert.Check(t, is.Equal(1, progress.skipped))
+}
+
+func TestRunCopyToContainerSync(t *testing.T) {
+	mtime := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	srcDir := fs.NewDir(t, "cp-test",
+		fs.WithFile("same", "content", fs.WithTimestamps(mtime, mtime)),
+		fs.WithFile("changed", "content", fs.WithTimestamps(mtime, mtime)),
+		fs.WithFile("new", "content", fs.WithTimestamps(mtime, mtime)))
+	defer srcDir.Remove()
+
+	var copied []string
+	fakeClient := &fakeClient{
+		containerStatPathFunc: func(container, path string) (types.ContainerPathStat, error) {
+			switch path {
+			case "/dst":
+				return types.ContainerPathStat{Name: "dst", Mode: os.ModeDir}, nil
+			case "/dst/same":
+				return types.ContainerPathStat{Name: "same", Size: 7, Mtime: mtime}, nil
+			case "/dst/changed":
+				return types.ContainerPathStat{Name: "changed", Size: 7, Mtime: mtime.Add(time.Hour)}, nil
+			}
+			return types.ContainerPathStat{}, errors.New("no such file or directory")
+		},
+		containerCopyToFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal("/dst", path))
+			copied = readTarEntries(t, content)
+			return nil
+		},
+	}
+	err := runCopy(test.NewFakeCli(fakeClient), copyOptions{source: srcDir.Path() + "/.", destination: "container:/dst", sync: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"./", "./changed", "./new"}, copied))
+}
+
+func TestRunCopyFromContainerSync(t *testing.T) {
+	mtime := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	srcDir := fs.NewDir(t, "cp-test",
+		fs.WithFile("same", "content", fs.WithTimestamps(mtime, mtime)),
+		fs.WithFile("changed", "new content", fs.WithTimestamps(mtime, mtime)))
+	defer srcDir.Remove()
+	// the content of a file which is up to date is not compared, so a file
+	// with the same size and modification time is skipped
+	destDir := fs.NewDir(t, "cp-test",
+		fs.WithFile("same", "CONTENT", fs.WithTimestamps(mtime, mtime)),
+		fs.WithFile("changed", "content", fs.WithTimestamps(mtime, mtime)))
+	defer destDir.Remove()
+
+	fakeClient := &fakeClient{
+		containerCopyFromFunc: func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+			readCloser, err := archive.TarWithOptions(srcDir.Path(), &archive.TarOptions{})
+			return readCloser, types.ContainerPathStat{Name: ".", Mode: os.ModeDir}, err
+		},
+	}
+	err := runCopy(test.NewFakeCli(fakeClient), copyOptions{source: "container:/src/.", destination: destDir.Path(), sync: true})
+	assert.NilError(t, err)
+
+	content, err := ioutil.ReadFile(destDir.Join("same"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("CONTENT", string(content)))
+	content, err = ioutil.ReadFile(destDir.Join("changed"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("new content", string(content)))
+}
@@ -1,9 +1,12 @@
 package container
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"runtime"
 	"strings"
 	"testing"
@@ -11,6 +14,7 @@ import (
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"gotest.tools/fs"
@@ -24,12 +28,31 @@ func TestRunCopyWithInvalidArguments(t *testing.T) {
 		expectedErr string
 	}{
 		{
-			doc: "copy between container",
+			doc: "source context without a source container",
 			options: copyOptions{
-				source:      "first:/path",
-				destination: "second:/path",
+				source:        "./source",
+				destination:   "second:/path",
+				sourceContext: "remote",
 			},
-			expectedErr: "copying between containers is not supported",
+			expectedErr: "--source-context can only be used when copying from a container",
+		},
+		{
+			doc: "destination context without a destination container",
+			options: copyOptions{
+				source:             "first:/path",
+				destination:        "./dest",
+				destinationContext: "remote",
+			},
+			expectedErr: "--destination-context can only be used when copying to a container",
+		},
+		{
+			doc: "sync from stdin",
+			options: copyOptions{
+				source:      "-",
+				destination: "container:/path",
+				sync:        true,
+			},
+			expectedErr: "--sync cannot be used to copy a tar archive from stdin or to stdout",
 		},
 		{
 			doc: "copy without a container",
@@ -143,6 +166,127 @@ func TestRunCopyToContainerSourceDoesNotExist(t *testing.T) {
 	assert.ErrorContains(t, err, expected)
 }
 
+// tarEntries returns an archive holding empty files with the given names
+func tarEntries(t *testing.T, names ...string) io.ReadCloser {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg}))
+	}
+	assert.NilError(t, tw.Close())
+	return ioutil.NopCloser(&buf)
+}
+
+func readTarEntries(t *testing.T, content io.Reader) []string {
+	var names []string
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestRunCopyAcrossContainers(t *testing.T) {
+	var testcases = []struct {
+		doc             string
+		options         copyOptions
+		expectedSrcPath string
+		expectedDstDir  string
+		expectedEntries []string
+	}{
+		{
+			doc:             "file into an existing directory",
+			options:         copyOptions{source: "first:/src/file", destination: "second:/dst"},
+			expectedSrcPath: "/src/file",
+			expectedDstDir:  "/dst",
+			expectedEntries: []string{"file"},
+		},
+		{
+			doc:             "file to a new name",
+			options:         copyOptions{source: "first:/src/file", destination: "second:/dst/renamed"},
+			expectedSrcPath: "/src/file",
+			expectedDstDir:  "/dst",
+			expectedEntries: []string{"renamed"},
+		},
+		{
+			doc:             "symbolic link not followed",
+			options:         copyOptions{source: "first:/src/link", destination: "second:/dst"},
+			expectedSrcPath: "/src/link",
+			expectedDstDir:  "/dst",
+			expectedEntries: []string{"link"},
+		},
+		{
+			doc:             "symbolic link followed",
+			options:         copyOptions{source: "first:/src/link", destination: "second:/dst", followLink: true},
+			expectedSrcPath: "/src/file",
+			expectedDstDir:  "/dst",
+			expectedEntries: []string{"link"},
+		},
+		{
+			doc:             "archive mode",
+			options:         copyOptions{source: "first:/src/file", destination: "second:/dst", copyUIDGID: true},
+			expectedSrcPath: "/src/file",
+			expectedDstDir:  "/dst",
+			expectedEntries: []string{"file"},
+		},
+	}
+	for _, testcase := range testcases {
+		testcase := testcase
+		t.Run(testcase.doc, func(t *testing.T) {
+			var copied bool
+			fakeClient := &fakeClient{
+				containerStatPathFunc: func(container, path string) (types.ContainerPathStat, error) {
+					switch container + ":" + path {
+					case "first:/src/link":
+						return types.ContainerPathStat{Name: "link", Mode: os.ModeSymlink, LinkTarget: "file"}, nil
+					case "first:/src/file":
+						return types.ContainerPathStat{Name: "file"}, nil
+					case "second:/dst":
+						return types.ContainerPathStat{Name: "dst", Mode: os.ModeDir}, nil
+					}
+					return types.ContainerPathStat{}, errors.New("no such file or directory")
+				},
+				containerCopyFromFunc: func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+					assert.Check(t, is.Equal("first", container))
+					assert.Check(t, is.Equal(testcase.expectedSrcPath, srcPath))
+					return tarEntries(t, path.Base(srcPath)), types.ContainerPathStat{}, nil
+				},
+				containerCopyToFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+					copied = true
+					assert.Check(t, is.Equal("second", container))
+					assert.Check(t, is.Equal(testcase.expectedDstDir, path))
+					assert.Check(t, is.Equal(testcase.options.copyUIDGID, options.CopyUIDGID))
+					assert.Check(t, is.DeepEqual(testcase.expectedEntries, readTarEntries(t, content)))
+					return nil
+				},
+			}
+			cli := test.NewFakeCli(fakeClient)
+			err := runCopy(cli, testcase.options)
+			assert.NilError(t, err)
+			assert.Check(t, copied)
+			assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+		})
+	}
+}
+
+func TestRunCopyAcrossContainersSourceError(t *testing.T) {
+	fakeClient := &fakeClient{
+		containerCopyFromFunc: func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+			return nil, types.ContainerPathStat{}, errors.New("no such container")
+		},
+		containerCopyToFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			t.Fatal("nothing should be copied to the destination container")
+			return nil
+		},
+	}
+	err := runCopy(test.NewFakeCli(fakeClient), copyOptions{source: "first:/src", destination: "second:/dst"})
+	assert.Error(t, err, "no such container")
+}
+
 func TestSplitCpArg(t *testing.T) {
 	var testcases = []struct {
 		doc               string
@@ -22,10 +22,22 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// Pull policies of the image of a container
+const (
+	// PullImageAlways pulls the image before creating the container
+	PullImageAlways = "always"
+	// PullImageMissing pulls the image if it is not found locally
+	PullImageMissing = "missing"
+	// PullImageNever never pulls the image, and fails if it is not found
+	// locally
+	PullImageNever = "never"
+)
+
 type createOptions struct {
 	name      string
 	platform  string
 	untrusted bool
+	pull      string
 }
 
 // NewCreateCommand creates a new cobra.Command for `docker create`
@@ -50,6 +62,7 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetInterspersed(false)
 
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
+	addPullFlag(flags, &opts.pull)
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -61,7 +74,23 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	return cmd
 }
 
+func addPullFlag(flags *pflag.FlagSet, pull *string) {
+	flags.StringVar(pull, "pull", PullImageMissing, `Pull image before creating ("`+PullImageAlways+`"|"`+PullImageMissing+`"|"`+PullImageNever+`")`)
+}
+
+func validatePullOpt(pull string) error {
+	switch pull {
+	case PullImageAlways, PullImageMissing, PullImageNever:
+		return nil
+	default:
+		return errors.Errorf("invalid pull option: '%s': must be one of %q, %q or %q", pull, PullImageAlways, PullImageMissing, PullImageNever)
+	}
+}
+
 func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, options *createOptions, copts *containerOptions) error {
+	if err := validatePullOpt(options.pull); err != nil {
+		return err
+	}
 	proxyConfig := dockerCli.ConfigFile().ParseProxyConfig(dockerCli.Client().DaemonHost(), opts.ConvertKVStringsToMapWithNil(copts.env.GetAll()))
 	newEnv := []string{}
 	for k, v := range proxyConfig {
@@ -181,6 +210,12 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig
 	networkingConfig := containerConfig.NetworkingConfig
 	stderr := dockerCli.Err()
 
+	// the image is pulled if it is missing, unless another policy is set
+	pull := opts.pull
+	if pull == "" {
+		pull = PullImageMissing
+	}
+
 	warnOnOomKillDisable(*hostConfig, stderr)
 	warnOnLocalhostDNS(*hostConfig, stderr)
 
@@ -212,23 +247,34 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig
 		}
 	}
 
+	pullAndTagImage := func() error {
+		// we don't want to write to stdout anything apart from container.ID
+		if err := pullImage(ctx, dockerCli, config.Image, opts.platform, stderr); err != nil {
+			return err
+		}
+		if taggedRef, ok := namedRef.(reference.NamedTagged); ok && trustedRef != nil {
+			return image.TagTrusted(ctx, dockerCli, trustedRef, taggedRef)
+		}
+		return nil
+	}
+
+	// an image referenced by its ID cannot be pulled
+	if pull == PullImageAlways && namedRef != nil {
+		if err := pullAndTagImage(); err != nil {
+			return nil, err
+		}
+	}
+
 	//create the container
 	response, err := dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, opts.name)
 
 	//if image not found try to pull it
 	if err != nil {
-		if apiclient.IsErrNotFound(err) && namedRef != nil {
+		if apiclient.IsErrNotFound(err) && namedRef != nil && pull == PullImageMissing {
 			fmt.Fprintf(stderr, "Unable to find image '%s' locally\n", reference.FamiliarString(namedRef))
-
-			// we don't want to write to stdout anything apart from container.ID
-			if err := pullImage(ctx, dockerCli, config.Image, opts.platform, stderr); err != nil {
+			if err := pullAndTagImage(); err != nil {
 				return nil, err
 			}
-			if taggedRef, ok := namedRef.(reference.NamedTagged); ok && trustedRef != nil {
-				if err := image.TagTrusted(ctx, dockerCli, trustedRef, taggedRef); err != nil {
-					return nil, err
-				}
-			}
 			// Retry
 			var retryErr error
 			response, retryErr = dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, opts.name)
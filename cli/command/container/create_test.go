@@ -124,6 +124,110 @@ func TestCreateContainerPullsImageIfMissing(t *testing.T) {
 	assert.Check(t, is.Contains(stderr, "Unable to find image 'does-not-exist-locally:latest' locally"))
 }
 
+func TestCreateContainerImagePullPolicy(t *testing.T) {
+	testCases := []struct {
+		doc            string
+		pull           string
+		imageExists    bool
+		expectedPulls  int
+		expectedCreate int
+		expectedError  string
+	}{
+		{
+			doc:            "missing, image exists",
+			pull:           PullImageMissing,
+			imageExists:    true,
+			expectedCreate: 1,
+		},
+		{
+			doc:            "missing, image missing",
+			pull:           PullImageMissing,
+			expectedPulls:  1,
+			expectedCreate: 2,
+		},
+		{
+			doc:            "always, image exists",
+			pull:           PullImageAlways,
+			imageExists:    true,
+			expectedPulls:  1,
+			expectedCreate: 1,
+		},
+		{
+			doc:            "never, image exists",
+			pull:           PullImageNever,
+			imageExists:    true,
+			expectedCreate: 1,
+		},
+		{
+			doc:            "never, image missing",
+			pull:           PullImageNever,
+			expectedCreate: 1,
+			expectedError:  "error fake not found",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			pulls, creates := 0, 0
+			client := &fakeClient{
+				createContainerFunc: func(
+					config *container.Config,
+					hostConfig *container.HostConfig,
+					networkingConfig *network.NetworkingConfig,
+					containerName string,
+				) (container.ContainerCreateCreatedBody, error) {
+					creates++
+					if !tc.imageExists && pulls == 0 {
+						return container.ContainerCreateCreatedBody{}, fakeNotFound{}
+					}
+					return container.ContainerCreateCreatedBody{ID: "abcdef"}, nil
+				},
+				imageCreateFunc: func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error) {
+					assert.Check(t, is.Equal("busybox", parentReference))
+					pulls++
+					return ioutil.NopCloser(strings.NewReader("")), nil
+				},
+				infoFunc: func() (types.Info, error) {
+					return types.Info{IndexServerAddress: "http://indexserver"}, nil
+				},
+			}
+			cli := test.NewFakeCli(client)
+			config := &containerConfig{
+				Config:     &container.Config{Image: "busybox"},
+				HostConfig: &container.HostConfig{},
+			}
+			_, err := createContainer(context.Background(), cli, config, &createOptions{
+				untrusted: true,
+				pull:      tc.pull,
+			})
+			if tc.expectedError != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedError))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.Equal(tc.expectedPulls, pulls))
+			assert.Check(t, is.Equal(tc.expectedCreate, creates))
+		})
+	}
+}
+
+func TestNewCreateCommandInvalidPullOption(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(config *container.Config,
+			hostConfig *container.HostConfig,
+			networkingConfig *network.NetworkingConfig,
+			containerName string,
+		) (container.ContainerCreateCreatedBody, error) {
+			return container.ContainerCreateCreatedBody{}, fmt.Errorf("shouldn't try to create the container")
+		},
+	})
+	cmd := NewCreateCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--pull", "sometimes", "busybox"})
+	err := cmd.Execute()
+	assert.Error(t, err, `invalid pull option: 'sometimes': must be one of "always", "missing" or "never"`)
+}
+
 func TestNewCreateCommandWithContentTrustErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -79,6 +79,12 @@ container2    --
 			formatter.Context{Format: "{{.Container}}  {{.CPUPerc}}"},
 			`container1  20.00%
 container2  --
+`,
+		},
+		{
+			formatter.Context{Format: NewStatsFormat(formatter.JSONFormatKey, "linux")},
+			`{"BlockIO":"20B / 20B","CPUPerc":"20.00%","Container":"container1","ID":"abcdef","MemPerc":"20.00%","MemUsage":"20B / 20B","Name":"foo","NetIO":"20B / 20B","PIDs":"2"}
+{"BlockIO":"--","CPUPerc":"--","Container":"container2","ID":"","MemPerc":"--","MemUsage":"-- / --","Name":"--","NetIO":"--","PIDs":"--"}
 `,
 		},
 	}
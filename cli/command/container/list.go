@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"io/ioutil"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -21,6 +22,7 @@ type psOptions struct {
 	nLatest bool
 	last    int
 	format  string
+	sort    string
 	filter  opts.FilterOpt
 }
 
@@ -46,6 +48,7 @@ func NewPsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.nLatest, "latest", "l", false, "Show the latest created container (includes all states)")
 	flags.IntVarP(&options.last, "last", "n", -1, "Show n last created containers (includes all states)")
 	flags.StringVarP(&options.format, "format", "", "", "Pretty-print containers using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -104,11 +107,23 @@ func buildContainerListOptions(opts *psOptions) (*types.ContainerListOptions, er
 		return nil, err
 	}
 	// At the moment all we need is to capture .Size for preprocessor
-	options.Size = opts.size || optionsProcessor["size"]
+	options.Size = opts.size || optionsProcessor["size"] || sortsOnSize(opts.sort)
 
 	return options, nil
 }
 
+// sortsOnSize returns whether the output is sorted on the size of the
+// containers, which is only computed by the daemon if requested
+func sortsOnSize(sortKeys string) bool {
+	for _, key := range strings.Split(sortKeys, ",") {
+		field := strings.SplitN(strings.TrimSpace(key), ":", 2)[0]
+		if strings.EqualFold(field, "size") {
+			return true
+		}
+	}
+	return false
+}
+
 func runPs(dockerCli command.Cli, options *psOptions) error {
 	ctx := context.Background()
 
@@ -135,6 +150,7 @@ func runPs(dockerCli command.Cli, options *psOptions) error {
 		Output: dockerCli.Out(),
 		Format: formatter.NewContainerFormat(format, options.quiet, listOptions.Size),
 		Trunc:  !options.noTrunc,
+		Sort:   options.sort,
 	}
 	return formatter.ContainerWrite(containerCtx, containers)
 }
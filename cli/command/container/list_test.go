@@ -11,6 +11,7 @@ import (
 	// Import builders to get the builder function as package function
 	. "github.com/docker/cli/internal/test/builders"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/golden"
 )
 
@@ -131,6 +132,35 @@ func TestContainerListFormatSizeSetsOption(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 }
 
+func TestContainerListSortSizeSetsOption(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options types.ContainerListOptions) ([]types.Container, error) {
+			assert.Check(t, options.Size)
+			return []types.Container{}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.Flags().Set("sort", "names,size:desc")
+	assert.NilError(t, cmd.Execute())
+}
+
+func TestContainerListWithSort(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
+			return []types.Container{
+				*Container("c10"),
+				*Container("c2"),
+				*Container("c1"),
+			}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.Flags().Set("format", "{{.Names}}")
+	cmd.Flags().Set("sort", "Names:desc")
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("c10\nc2\nc1\n", cli.OutBuffer().String()))
+}
+
 func TestContainerListWithConfigFormat(t *testing.T) {
 	cli := test.NewFakeCli(&fakeClient{
 		containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/spf13/cobra"
@@ -18,54 +19,60 @@ type logsOptions struct {
 	timestamps bool
 	details    bool
 	tail       string
+	noColor    bool
+	filter     opts.FilterOpt
 
-	container string
+	containers []string
 }
 
 // NewLogsCommand creates a new cobra.Command for `docker logs`
 func NewLogsCommand(dockerCli command.Cli) *cobra.Command {
-	var opts logsOptions
+	options := logsOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
-		Use:   "logs [OPTIONS] CONTAINER",
-		Short: "Fetch the logs of a container",
-		Args:  cli.ExactArgs(1),
+		Use:   "logs [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Fetch the logs of one or more containers",
+		Args: func(cmd *cobra.Command, args []string) error {
+			// the containers can be selected with --filter instead
+			if options.filter.Value().Len() > 0 {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.container = args[0]
-			return runLogs(dockerCli, &opts)
+			options.containers = args
+			return runLogs(dockerCli, &options)
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
-	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
-	flags.StringVar(&opts.until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.BoolVarP(&options.follow, "follow", "f", false, "Follow log output")
+	flags.StringVar(&options.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.StringVar(&options.until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
 	flags.SetAnnotation("until", "version", []string{"1.35"})
-	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
-	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
-	flags.StringVar(&opts.tail, "tail", "all", "Number of lines to show from the end of the logs")
+	flags.BoolVarP(&options.timestamps, "timestamps", "t", false, "Show timestamps")
+	flags.BoolVar(&options.details, "details", false, "Show extra details provided to logs")
+	flags.StringVar(&options.tail, "tail", "all", "Number of lines to show from the end of the logs")
+	flags.Var(&options.filter, "filter", "Fetch the logs of the containers matching the filters")
+	flags.BoolVar(&options.noColor, "no-color", false, "Do not color the names of the containers")
 	return cmd
 }
 
 func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 	ctx := context.Background()
 
-	c, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
+	// the logs of several containers are multiplexed, with the name of the
+	// container as prefix
+	if len(opts.containers) > 1 || opts.filter.Value().Len() > 0 {
+		return runMultiLogs(ctx, dockerCli, opts)
+	}
+
+	c, err := dockerCli.Client().ContainerInspect(ctx, opts.containers[0])
 	if err != nil {
 		return err
 	}
 
-	options := types.ContainerLogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Since:      opts.since,
-		Until:      opts.until,
-		Timestamps: opts.timestamps,
-		Follow:     opts.follow,
-		Tail:       opts.tail,
-		Details:    opts.details,
-	}
-	responseBody, err := dockerCli.Client().ContainerLogs(ctx, c.ID, options)
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, c.ID, opts.containerLogsOptions())
 	if err != nil {
 		return err
 	}
@@ -78,3 +85,16 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 	}
 	return err
 }
+
+func (opts *logsOptions) containerLogsOptions() types.ContainerLogsOptions {
+	return types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.since,
+		Until:      opts.until,
+		Timestamps: opts.timestamps,
+		Follow:     opts.follow,
+		Tail:       opts.tail,
+		Details:    opts.details,
+	}
+}
@@ -0,0 +1,402 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	apiclient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// logsColors are the ANSI colors of the names of the containers
+var logsColors = []int{36, 33, 32, 35, 34, 96, 93, 92, 95, 94}
+
+// logSource is a container whose logs are multiplexed
+type logSource struct {
+	id     string
+	name   string
+	tty    bool
+	prefix string
+}
+
+// logLine is a line of the logs of a container, with its trailing newline
+type logLine struct {
+	source *logSource
+	text   string
+	stderr bool
+	// timestamp is only set when the logs are fetched with their timestamps
+	timestamp time.Time
+}
+
+// logsPrinter prints the lines of the logs of several containers, with the
+// names of the containers as prefixes
+type logsPrinter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	err    io.Writer
+	color  bool
+	width  int
+	colors int
+}
+
+func newLogsPrinter(dockerCli command.Cli, opts *logsOptions, sources []*logSource) *logsPrinter {
+	p := &logsPrinter{
+		out:   dockerCli.Out(),
+		err:   dockerCli.Err(),
+		color: !opts.noColor && dockerCli.Out().IsTerminal(),
+	}
+	for _, s := range sources {
+		if len(s.name) > p.width {
+			p.width = len(s.name)
+		}
+	}
+	for _, s := range sources {
+		p.setPrefix(s)
+	}
+	return p
+}
+
+// setPrefix sets the prefix of the lines of source, with the next color
+func (p *logsPrinter) setPrefix(source *logSource) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(source.name) > p.width {
+		p.width = len(source.name)
+	}
+	source.prefix = fmt.Sprintf("%-*s | ", p.width, source.name)
+	if p.color {
+		source.prefix = fmt.Sprintf("\033[%dm%-*s |\033[0m ", logsColors[p.colors%len(logsColors)], p.width, source.name)
+	}
+	p.colors++
+}
+
+func (p *logsPrinter) print(line logLine) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w := p.out
+	if line.stderr {
+		w = p.err
+	}
+	fmt.Fprint(w, line.source.prefix, line.text)
+}
+
+func (p *logsPrinter) printError(source *logSource, err error) {
+	p.print(logLine{source: source, text: fmt.Sprintf("error from daemon in stream: %v\n", err), stderr: true})
+}
+
+// logLineWriter splits the logs written to it in lines
+type logLineWriter struct {
+	source     *logSource
+	stderr     bool
+	timestamps bool
+	handle     func(logLine)
+	buf        []byte
+	last       time.Time
+}
+
+func (w *logLineWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+		w.emit(string(w.buf[:i+1]))
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush emits the last line, if it does not end with a newline
+func (w *logLineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf) + "\n")
+		w.buf = nil
+	}
+}
+
+func (w *logLineWriter) emit(text string) {
+	line := logLine{source: w.source, text: text, stderr: w.stderr}
+	if w.timestamps {
+		// the lines which do not start with a timestamp, such as the
+		// continuation of a line of a TTY, are sorted after the previous one
+		if i := strings.IndexByte(text, ' '); i > 0 {
+			if ts, err := time.Parse(time.RFC3339Nano, text[:i]); err == nil {
+				w.last = ts
+			}
+		}
+		line.timestamp = w.last
+	}
+	w.handle(line)
+}
+
+// readLogs fetches the logs of source, and calls handle for each line
+func readLogs(ctx context.Context, client apiclient.APIClient, source *logSource, options types.ContainerLogsOptions, handle func(logLine)) error {
+	body, err := client.ContainerLogs(ctx, source.id, options)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	stdout := &logLineWriter{source: source, timestamps: options.Timestamps, handle: handle}
+	stderr := &logLineWriter{source: source, stderr: true, timestamps: options.Timestamps, handle: handle}
+	if source.tty {
+		_, err = io.Copy(stdout, body)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, body)
+	}
+	stdout.Flush()
+	stderr.Flush()
+	return err
+}
+
+// logSources returns the containers whose logs are fetched: the containers
+// named on the command line, and the containers matching the filters
+func logSources(ctx context.Context, client apiclient.APIClient, opts *logsOptions) ([]*logSource, error) {
+	ids := append([]string{}, opts.containers...)
+	if opts.filter.Value().Len() > 0 {
+		containers, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: opts.filter.Value()})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(containers, func(i, j int) bool {
+			return containerName(containers[i]) < containerName(containers[j])
+		})
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	var sources []*logSource
+	seen := map[string]bool{}
+	for _, id := range ids {
+		source, err := inspectLogSource(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[source.id] {
+			seen[source.id] = true
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+func inspectLogSource(ctx context.Context, client apiclient.APIClient, container string) (*logSource, error) {
+	c, err := client.ContainerInspect(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+	return &logSource{id: c.ID, name: strings.TrimPrefix(c.Name, "/"), tty: c.Config.Tty}, nil
+}
+
+func containerName(c types.Container) string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// runMultiLogs multiplexes the logs of several containers. The lines which
+// are already written are printed in timestamp order if the logs are fetched
+// with their timestamps. When following the logs, the containers which are
+// started are followed too, until the command is interrupted.
+func runMultiLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
+	client := dockerCli.Client()
+	sources, err := logSources(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	printer := newLogsPrinter(dockerCli, opts, sources)
+	options := opts.containerLogsOptions()
+
+	var (
+		eventq <-chan events.Message
+		errq   <-chan error
+	)
+	if opts.follow {
+		// subscribe to the events before fetching the logs, so that no
+		// container start is missed
+		f := filters.NewArgs()
+		f.Add("type", "container")
+		f.Add("event", "start")
+		f.Add("event", "die")
+		eventq, errq = client.Events(ctx, types.EventsOptions{Filters: f})
+	}
+
+	if opts.timestamps {
+		now := time.Now()
+		backlog := options
+		backlog.Follow = false
+		if opts.follow && opts.until == "" {
+			backlog.Until = formatLogsTime(now)
+		}
+		if err := printSortedLogs(ctx, client, printer, sources, backlog); err != nil || !opts.follow {
+			return err
+		}
+		options.Since = formatLogsTime(now)
+		options.Tail = "all"
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	streamLogs := func(source *logSource, options types.ContainerLogsOptions) {
+		defer wg.Done()
+		if err := readLogs(ctx, client, source, options, printer.print); err != nil && ctx.Err() == nil {
+			if opts.follow {
+				printer.printError(source, err)
+				return
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	}
+	// followSince is the time from which the logs of a container are
+	// fetched again when it is restarted
+	followSince := map[string]string{}
+	known := map[string]*logSource{}
+	for _, source := range sources {
+		followSince[source.id] = formatLogsTime(time.Now())
+		known[source.id] = source
+		wg.Add(1)
+		go streamLogs(source, options)
+	}
+	if !opts.follow {
+		wg.Wait()
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
+
+	followed := func(e events.Message) bool {
+		if _, ok := known[e.ID]; ok {
+			return true
+		}
+		for _, name := range opts.containers {
+			if name == e.Actor.Attributes["name"] {
+				return true
+			}
+		}
+		if opts.filter.Value().Len() == 0 {
+			return false
+		}
+		f := opts.filter.Value().Clone()
+		f.Add("id", e.ID)
+		containers, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: f})
+		return err == nil && len(containers) > 0
+	}
+	for {
+		select {
+		case e := <-eventq:
+			switch e.Action {
+			case "die":
+				// a restarted container is followed from the time it stopped
+				if _, ok := known[e.ID]; ok {
+					followSince[e.ID] = formatLogsTime(time.Unix(0, e.TimeNano))
+				}
+			case "start":
+				if !followed(e) {
+					continue
+				}
+				sourceOptions := options
+				source, ok := known[e.ID]
+				if ok {
+					sourceOptions.Since = followSince[e.ID]
+					sourceOptions.Tail = "all"
+				} else {
+					if source, err = inspectLogSource(ctx, client, e.ID); err != nil {
+						continue
+					}
+					printer.setPrefix(source)
+					known[e.ID] = source
+				}
+				followSince[e.ID] = formatLogsTime(time.Unix(0, e.TimeNano))
+				wg.Add(1)
+				go streamLogs(source, sourceOptions)
+			}
+		case err := <-errq:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// logsQueueSize is the number of lines of a container which are read ahead
+// while the lines of the other containers are merged
+const logsQueueSize = 64
+
+// printSortedLogs fetches the logs of the containers, and prints their lines
+// in timestamp order. The lines of each container are in timestamp order, so
+// they are merged as they are read: the next line printed is the earliest of
+// the next lines of the containers, which are read once it is printed.
+func printSortedLogs(ctx context.Context, client apiclient.APIClient, printer *logsPrinter, sources []*logSource, options types.ContainerLogsOptions) error {
+	var (
+		queues = make([]chan logLine, len(sources))
+		errs   = make([]error, len(sources))
+	)
+	for i, source := range sources {
+		queues[i] = make(chan logLine, logsQueueSize)
+		go func(i int, source *logSource) {
+			defer close(queues[i])
+			errs[i] = readLogs(ctx, client, source, options, func(line logLine) {
+				queues[i] <- line
+			})
+		}(i, source)
+	}
+
+	heads := make([]*logLine, len(sources))
+	for i := range queues {
+		heads[i] = nextLogLine(queues[i])
+	}
+	for {
+		next := -1
+		for i, head := range heads {
+			if head != nil && (next < 0 || head.timestamp.Before(heads[next].timestamp)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		printer.print(*heads[next])
+		heads[next] = nextLogLine(queues[next])
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextLogLine returns the next line of queue, or nil once the logs are read
+func nextLogLine(queue <-chan logLine) *logLine {
+	line, ok := <-queue
+	if !ok {
+		return nil
+	}
+	return &line
+}
+
+// formatLogsTime formats t as a timestamp of the logs API
+func formatLogsTime(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
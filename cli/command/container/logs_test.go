@@ -1,16 +1,25 @@
 package container
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/stdcopy"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
 )
 
 var logFn = func(expectedOut string) func(string, types.ContainerLogsOptions) (io.ReadCloser, error) {
@@ -38,7 +47,7 @@ func TestRunLogs(t *testing.T) {
 		{
 			doc:         "successful logs",
 			expectedOut: "foo",
-			options:     &logsOptions{},
+			options:     &logsOptions{containers: []string{"container"}},
 			client:      fakeClient{logFunc: logFn("foo"), inspectFunc: inspectFn},
 		},
 	}
@@ -60,3 +69,183 @@ func TestRunLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLogsCommandArgs(t *testing.T) {
+	cmd := NewLogsCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{})
+	assert.ErrorContains(t, cmd.Execute(), `"logs" requires at least 1 argument.`)
+
+	cmd = NewLogsCommand(test.NewFakeCli(&fakeClient{}))
+	assert.NilError(t, cmd.ParseFlags([]string{"--filter", "name=web"}))
+	assert.NilError(t, cmd.ValidateArgs(nil))
+}
+
+func multiLogsClient(logs map[string]string) *fakeClient {
+	return &fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: name + "-id", Name: "/" + name},
+				Config:            &container.Config{Tty: true},
+			}, nil
+		},
+		logFunc: func(container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(logs[strings.TrimSuffix(container, "-id")])), nil
+		},
+	}
+}
+
+func TestRunMultiLogs(t *testing.T) {
+	client := multiLogsClient(map[string]string{
+		"web":    "web line\nunterminated",
+		"worker": "worker line\n",
+	})
+	cli := test.NewFakeCli(client)
+	err := runLogs(cli, &logsOptions{containers: []string{"web", "worker", "web"}})
+	assert.NilError(t, err)
+
+	lines := strings.SplitAfter(cli.OutBuffer().String(), "\n")
+	sort.Strings(lines)
+	assert.Check(t, is.DeepEqual([]string{"", "web    | unterminated\n", "web    | web line\n", "worker | worker line\n"}, lines))
+}
+
+func TestRunMultiLogsStderr(t *testing.T) {
+	var logs bytes.Buffer
+	stdcopy.NewStdWriter(&logs, stdcopy.Stdout).Write([]byte("out\n"))
+	stdcopy.NewStdWriter(&logs, stdcopy.Stderr).Write([]byte("err\n"))
+	client := multiLogsClient(map[string]string{"web": logs.String()})
+	client.inspectFunc = func(name string) (types.ContainerJSON, error) {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: name + "-id", Name: "/" + name},
+			Config:            &container.Config{},
+		}, nil
+	}
+	filter := opts.NewFilterOpt()
+	assert.NilError(t, filter.Set("name=web"))
+	client.containerListFunc = func(options types.ContainerListOptions) ([]types.Container, error) {
+		assert.Check(t, options.All)
+		assert.Check(t, is.DeepEqual([]string{"web"}, options.Filters.Get("name")))
+		return []types.Container{{ID: "web", Names: []string{"/web"}}}, nil
+	}
+	cli := test.NewFakeCli(client)
+	err := runLogs(cli, &logsOptions{filter: filter})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("web | out\n", cli.OutBuffer().String()))
+	assert.Check(t, is.Equal("web | err\n", cli.ErrBuffer().String()))
+}
+
+func TestRunMultiLogsTimestamps(t *testing.T) {
+	client := multiLogsClient(map[string]string{
+		"web":    "2019-01-01T00:00:01.000000000Z one\n2019-01-01T00:00:03.000000000Z three\ncontinued\n",
+		"worker": "2019-01-01T00:00:02.000000000Z two\n2019-01-01T00:00:04.000000000Z four\n",
+	})
+	cli := test.NewFakeCli(client)
+	err := runLogs(cli, &logsOptions{containers: []string{"web", "worker"}, timestamps: true})
+	assert.NilError(t, err)
+	expected := `web    | 2019-01-01T00:00:01.000000000Z one
+worker | 2019-01-01T00:00:02.000000000Z two
+web    | 2019-01-01T00:00:03.000000000Z three
+web    | continued
+worker | 2019-01-01T00:00:04.000000000Z four
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestRunMultiLogsTimestampsMergedAsRead(t *testing.T) {
+	client := multiLogsClient(map[string]string{
+		"worker": "2019-01-01T00:00:02.000000000Z two\n2019-01-01T00:00:04.000000000Z four\n",
+	})
+	webLogs, webWriter := io.Pipe()
+	logFunc := client.logFunc
+	client.logFunc = func(container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+		if container == "web-id" {
+			return webLogs, nil
+		}
+		return logFunc(container, options)
+	}
+	var out lockedBuffer
+	cli := test.NewFakeCli(client)
+	cli.SetOut(streams.NewOut(&out))
+	done := make(chan error)
+	go func() {
+		done <- runLogs(cli, &logsOptions{containers: []string{"web", "worker"}, timestamps: true})
+	}()
+
+	// the lines are printed before all the logs of web are read
+	_, err := io.WriteString(webWriter, "2019-01-01T00:00:01.000000000Z one\n2019-01-01T00:00:03.000000000Z three\n")
+	assert.NilError(t, err)
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if strings.Contains(out.String(), "worker | 2019-01-01T00:00:02.000000000Z two\n") {
+			return poll.Success()
+		}
+		return poll.Continue("waiting for the first lines of the logs")
+	})
+	_, err = io.WriteString(webWriter, "2019-01-01T00:00:05.000000000Z five\n")
+	assert.NilError(t, err)
+	assert.NilError(t, webWriter.Close())
+	assert.NilError(t, <-done)
+	expected := `web    | 2019-01-01T00:00:01.000000000Z one
+worker | 2019-01-01T00:00:02.000000000Z two
+web    | 2019-01-01T00:00:03.000000000Z three
+worker | 2019-01-01T00:00:04.000000000Z four
+web    | 2019-01-01T00:00:05.000000000Z five
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+}
+
+// lockedBuffer is a buffer which can be read while the logs are written
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunMultiLogsFollowStartedContainer(t *testing.T) {
+	client := multiLogsClient(map[string]string{
+		"web":    "web line\n",
+		"worker": "worker line\n",
+	})
+	eventq := make(chan events.Message, 1)
+	client.eventsFunc = func(options types.EventsOptions) (<-chan events.Message, <-chan error) {
+		actions := options.Filters.Get("event")
+		sort.Strings(actions)
+		assert.Check(t, is.DeepEqual([]string{"die", "start"}, actions))
+		return eventq, make(chan error)
+	}
+	var out lockedBuffer
+	cli := test.NewFakeCli(client)
+	cli.SetOut(streams.NewOut(&out))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- runMultiLogs(ctx, cli, &logsOptions{containers: []string{"web", "worker"}, follow: true})
+	}()
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if strings.Contains(out.String(), "worker | worker line\n") {
+			return poll.Success()
+		}
+		return poll.Continue("waiting for the logs of worker")
+	})
+	// the logs of a restarted container are fetched again from the time it stopped
+	eventq <- events.Message{ID: "web-id", Action: "start", Actor: events.Actor{ID: "web-id", Attributes: map[string]string{"name": "web"}}}
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if strings.Count(out.String(), "web    | web line\n") == 2 {
+			return poll.Success()
+		}
+		return poll.Continue("waiting for the logs of the restarted container")
+	})
+	cancel()
+	assert.NilError(t, <-done)
+}
@@ -27,6 +27,18 @@ func TestRunLabel(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 }
 
+func TestRunCommandInvalidPullOption(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(_ *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ string) (container.ContainerCreateCreatedBody, error) {
+			return container.ContainerCreateCreatedBody{}, fmt.Errorf("shouldn't try to create the container")
+		},
+	})
+	cmd := NewRunCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--pull", "sometimes", "busybox"})
+	assert.Error(t, cmd.Execute(), `invalid pull option: 'sometimes': must be one of "always", "missing" or "never"`)
+}
+
 func TestRunCommandWithContentTrustErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
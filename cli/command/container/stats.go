@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,8 @@ type statsOptions struct {
 	noStream   bool
 	noTrunc    bool
 	format     string
+	raw        bool
+	prometheus string
 	containers []string
 }
 
@@ -45,6 +48,8 @@ func NewStatsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.noStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Do not truncate output")
 	flags.StringVar(&opts.format, "format", "", "Pretty-print images using a Go template")
+	flags.BoolVar(&opts.raw, "raw", false, "Write the raw values of the statistics as newline-delimited JSON")
+	flags.StringVar(&opts.prometheus, "prometheus", "", "Serve the statistics in the Prometheus format on this address (e.g. 127.0.0.1:9323) instead of printing them")
 	return cmd
 }
 
@@ -57,6 +62,30 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 
 	ctx := context.Background()
 
+	if opts.raw && opts.format != "" {
+		return errors.New("--raw cannot be used with --format")
+	}
+
+	// listen first, so that an invalid or busy address is reported before
+	// collecting the statistics
+	var metricsListener net.Listener
+	if opts.prometheus != "" {
+		if opts.noStream {
+			return errors.New("--prometheus cannot be used with --no-stream")
+		}
+		if opts.format != "" || opts.raw {
+			return errors.New("--prometheus cannot be used with --format or --raw")
+		}
+		var err error
+		if metricsListener, err = net.Listen("tcp", opts.prometheus); err != nil {
+			return err
+		}
+		defer metricsListener.Close()
+		if !isLoopbackAddr(metricsListener.Addr()) {
+			fmt.Fprintf(dockerCli.Err(), "WARNING: the statistics are served without authentication on %s, which is not a loopback address\n", metricsListener.Addr())
+		}
+	}
+
 	// monitorContainerEvents watches for container creation and removal (only
 	// used when calling `docker stats` without arguments).
 	monitorContainerEvents := func(started chan<- struct{}, c chan events.Message) {
@@ -188,6 +217,11 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 
 	// before print to screen, make sure each container get at least one valid stat data
 	waitFirst.Wait()
+	if metricsListener != nil {
+		fmt.Fprintf(dockerCli.Out(), "Serving the statistics on http://%s/metrics\n", metricsListener.Addr())
+		handler := &statsMetricsHandler{ctx: ctx, client: dockerCli.Client(), cStats: &cStats}
+		return serveStatsMetrics(metricsListener, handler, closeChan)
+	}
 	format := opts.format
 	if len(format) == 0 {
 		if len(dockerCli.ConfigFile().StatsFormat) > 0 {
@@ -201,7 +235,8 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 		Format: NewStatsFormat(format, daemonOSType),
 	}
 	cleanScreen := func() {
-		if !opts.noStream {
+		// json output is meant to be streamed to another program
+		if !opts.noStream && !statsCtx.Format.IsJSON() && !opts.raw {
 			fmt.Fprint(dockerCli.Out(), "\033[2J")
 			fmt.Fprint(dockerCli.Out(), "\033[H")
 		}
@@ -216,7 +251,12 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 			ccstats = append(ccstats, c.GetStatistics())
 		}
 		cStats.mu.Unlock()
-		if err = statsFormatWrite(statsCtx, ccstats, daemonOSType, !opts.noTrunc); err != nil {
+		if opts.raw {
+			err = writeStatsJSONLines(dockerCli.Out(), ccstats, time.Now())
+		} else {
+			err = statsFormatWrite(statsCtx, ccstats, daemonOSType, !opts.noTrunc)
+		}
+		if err != nil {
 			break
 		}
 		if len(cStats.cs) == 0 && !showAll {
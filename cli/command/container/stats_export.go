@@ -0,0 +1,229 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// statsJSONLine is a line of the newline-delimited JSON output of the
+// statistics of a container
+type statsJSONLine struct {
+	Time time.Time
+	StatsEntry
+}
+
+// writeStatsJSONLines writes the statistics of the containers at t, one JSON
+// object per line. Unlike the "json" format, which writes the human-readable
+// values of the table columns, it writes the numbers of the StatsEntry.
+func writeStatsJSONLines(out io.Writer, entries []StatsEntry, t time.Time) error {
+	enc := json.NewEncoder(out)
+	for _, e := range entries {
+		e.Name = strings.TrimPrefix(e.Name, "/")
+		if err := enc.Encode(statsJSONLine{Time: t.UTC(), StatsEntry: e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statsMetric describes a metric of the Prometheus exposition of the
+// statistics
+type statsMetric struct {
+	name  string
+	help  string
+	kind  string
+	value func(StatsEntry) float64
+	// unix is set for the metrics which are only reported on Linux
+	unix bool
+}
+
+var statsMetrics = []statsMetric{
+	{name: "docker_container_cpu_percent", help: "CPU usage of the container, in percent of a CPU.", kind: "gauge", value: func(e StatsEntry) float64 { return e.CPUPercentage }},
+	{name: "docker_container_memory_usage_bytes", help: "Memory usage of the container, without the page cache on Linux, and the private working set on Windows.", kind: "gauge", value: func(e StatsEntry) float64 { return e.Memory }},
+	{name: "docker_container_memory_limit_bytes", help: "Memory limit of the container.", kind: "gauge", value: func(e StatsEntry) float64 { return e.MemoryLimit }, unix: true},
+	{name: "docker_container_memory_percent", help: "Memory usage of the container, in percent of its limit.", kind: "gauge", value: func(e StatsEntry) float64 { return e.MemoryPercentage }, unix: true},
+	{name: "docker_container_network_receive_bytes_total", help: "Bytes received by the container over the network.", kind: "counter", value: func(e StatsEntry) float64 { return e.NetworkRx }},
+	{name: "docker_container_network_transmit_bytes_total", help: "Bytes sent by the container over the network.", kind: "counter", value: func(e StatsEntry) float64 { return e.NetworkTx }},
+	{name: "docker_container_block_read_bytes_total", help: "Bytes read by the container from block devices.", kind: "counter", value: func(e StatsEntry) float64 { return e.BlockRead }},
+	{name: "docker_container_block_write_bytes_total", help: "Bytes written by the container to block devices.", kind: "counter", value: func(e StatsEntry) float64 { return e.BlockWrite }},
+	{name: "docker_container_pids", help: "Number of processes and threads of the container.", kind: "gauge", value: func(e StatsEntry) float64 { return float64(e.PidsCurrent) }, unix: true},
+}
+
+var invalidMetricLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// metricLabelName returns the name of the metric label of a container label
+func metricLabelName(label string) string {
+	return "label_" + invalidMetricLabelChars.ReplaceAllString(label, "_")
+}
+
+var metricLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeStatsMetrics writes the statistics of the containers in the
+// Prometheus text exposition format. The containers are identified by their
+// ID and name, and their labels are added to the metric labels. The invalid
+// statistics are not written.
+func writeStatsMetrics(out io.Writer, entries []StatsEntry, labels map[string]map[string]string, osType string) error {
+	var valid []StatsEntry
+	for _, e := range entries {
+		if !e.IsInvalid && e.ID != "" {
+			valid = append(valid, e)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool {
+		return valid[i].Name < valid[j].Name
+	})
+
+	metricLabels := make([]string, len(valid))
+	for i, e := range valid {
+		pairs := []string{
+			fmt.Sprintf(`id="%s"`, e.ID),
+			fmt.Sprintf(`name="%s"`, metricLabelValueReplacer.Replace(strings.TrimPrefix(e.Name, "/"))),
+		}
+		containerLabels := labels[e.ID]
+		keys := make([]string, 0, len(containerLabels))
+		for k := range containerLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		seen := map[string]bool{}
+		for _, k := range keys {
+			name := metricLabelName(k)
+			// labels which differ only by their invalid characters keep
+			// the first value
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, metricLabelValueReplacer.Replace(containerLabels[k])))
+		}
+		metricLabels[i] = "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	for _, m := range statsMetrics {
+		if m.unix && osType == winOSType {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for i, e := range valid {
+			if _, err := fmt.Fprintf(out, "%s%s %s\n", m.name, metricLabels[i], strconv.FormatFloat(m.value(e), 'f', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// statsMetricsHandler serves the statistics collected in cStats in the
+// Prometheus text exposition format
+type statsMetricsHandler struct {
+	ctx    context.Context
+	client client.APIClient
+	cStats *stats
+
+	mu sync.Mutex
+	// labels are the labels of the containers, by ID
+	labels map[string]map[string]string
+}
+
+func (h *statsMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.cStats.mu.Lock()
+	entries := make([]StatsEntry, 0, len(h.cStats.cs))
+	for _, c := range h.cStats.cs {
+		entries = append(entries, c.GetStatistics())
+	}
+	h.cStats.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeStatsMetrics(w, entries, h.containerLabels(entries), daemonOSType)
+}
+
+// containerLabels returns the labels of the containers of the statistics,
+// inspecting the containers which were not seen before
+func (h *statsMetricsHandler) containerLabels(entries []StatsEntry) map[string]map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.labels == nil {
+		h.labels = map[string]map[string]string{}
+	}
+	for _, e := range entries {
+		if _, ok := h.labels[e.ID]; ok || e.ID == "" {
+			continue
+		}
+		c, err := h.client.ContainerInspect(h.ctx, e.ID)
+		if err != nil {
+			// the container may have been removed; its labels are
+			// looked up again on the next scrape
+			continue
+		}
+		h.labels[e.ID] = c.Config.Labels
+	}
+	// the labels of the containers which are no longer monitored are
+	// forgotten
+	labels := map[string]map[string]string{}
+	for _, e := range entries {
+		if l, ok := h.labels[e.ID]; ok {
+			labels[e.ID] = l
+		}
+	}
+	h.labels = labels
+	return labels
+}
+
+// statsMetricsReadHeaderTimeout is how long the metrics server waits for the
+// headers of a request
+const statsMetricsReadHeaderTimeout = 10 * time.Second
+
+// isLoopbackAddr returns whether addr only accepts connections from the host
+func isLoopbackAddr(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
+
+// serveStatsMetrics serves the statistics on l at /metrics, until the
+// server or the collection of the statistics fails
+func serveStatsMetrics(l net.Listener, handler http.Handler, closeChan chan error) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: statsMetricsReadHeaderTimeout,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(l)
+	}()
+	for {
+		select {
+		case err := <-serveErr:
+			return err
+		case err, ok := <-closeChan:
+			if !ok {
+				// the containers were given on the command line, and no
+				// error is expected
+				closeChan = nil
+				continue
+			}
+			if err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			if err != nil {
+				l.Close()
+				return err
+			}
+		}
+	}
+}
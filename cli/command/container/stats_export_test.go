@@ -0,0 +1,114 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func testStatsEntries() []StatsEntry {
+	return []StatsEntry{
+		{
+			Container:        "web",
+			Name:             "/web",
+			ID:               "7c0c4d8b4a3e",
+			CPUPercentage:    12.5,
+			Memory:           104857600,
+			MemoryLimit:      2147483648,
+			MemoryPercentage: 4.8828125,
+			NetworkRx:        1024,
+			NetworkTx:        2048,
+			BlockRead:        4096,
+			BlockWrite:       8192,
+			PidsCurrent:      7,
+		},
+		{
+			Container: "db",
+			Name:      "/db",
+			ID:        "3f7a9e1c2b5d",
+			IsInvalid: true,
+		},
+	}
+}
+
+func TestWriteStatsJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	assert.NilError(t, writeStatsJSONLines(&out, testStatsEntries(), time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)))
+	golden.Assert(t, out.String(), "container-stats-jsonlines.golden")
+}
+
+func TestRunStatsExportOptionConflicts(t *testing.T) {
+	testCases := []struct {
+		opts          statsOptions
+		expectedError string
+	}{
+		{opts: statsOptions{raw: true, format: "json"}, expectedError: "--raw cannot be used with --format"},
+		{opts: statsOptions{prometheus: "127.0.0.1:0", raw: true}, expectedError: "--prometheus cannot be used with --format or --raw"},
+		{opts: statsOptions{prometheus: "127.0.0.1:0", format: "json"}, expectedError: "--prometheus cannot be used with --format or --raw"},
+		{opts: statsOptions{prometheus: "127.0.0.1:0", noStream: true}, expectedError: "--prometheus cannot be used with --no-stream"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		cli := test.NewFakeCli(&fakeClient{})
+		assert.Error(t, runStats(cli, &tc.opts), tc.expectedError)
+	}
+}
+
+func TestWriteStatsMetrics(t *testing.T) {
+	labels := map[string]map[string]string{
+		"7c0c4d8b4a3e": {"com.example.app": "shop", "tier": `front "end"`},
+	}
+	var out bytes.Buffer
+	assert.NilError(t, writeStatsMetrics(&out, testStatsEntries(), labels, "linux"))
+	golden.Assert(t, out.String(), "container-stats-prometheus.golden")
+
+	out.Reset()
+	assert.NilError(t, writeStatsMetrics(&out, testStatsEntries(), labels, winOSType))
+	assert.Check(t, !bytes.Contains(out.Bytes(), []byte("docker_container_pids")))
+}
+
+func TestStatsMetricsHandlerLabels(t *testing.T) {
+	inspected := 0
+	client := &fakeClient{
+		inspectFunc: func(id string) (types.ContainerJSON, error) {
+			inspected++
+			return types.ContainerJSON{Config: &container.Config{Labels: map[string]string{"app": "shop"}}}, nil
+		},
+	}
+	s := NewStats("web")
+	s.SetStatistics(testStatsEntries()[0])
+	handler := &statsMetricsHandler{ctx: context.Background(), client: client, cStats: &stats{cs: []*Stats{s}}}
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		assert.Check(t, is.Contains(rec.Body.String(), `docker_container_pids{id="7c0c4d8b4a3e",name="web",label_app="shop"} 7`))
+	}
+	assert.Check(t, is.Equal(1, inspected))
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	testCases := []struct {
+		addr     net.Addr
+		expected bool
+	}{
+		{addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9323}, expected: true},
+		{addr: &net.TCPAddr{IP: net.ParseIP("::1"), Port: 9323}, expected: true},
+		{addr: &net.TCPAddr{IP: net.IPv6zero, Port: 9323}, expected: false},
+		{addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.2"), Port: 9323}, expected: false},
+		{addr: &net.UnixAddr{Name: "/run/stats.sock", Net: "unix"}, expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(tc.expected, isLoopbackAddr(tc.addr)), tc.addr.String())
+	}
+}
@@ -1,15 +1,18 @@
 package command
 
 import (
-	"errors"
-
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/pkg/errors"
 )
 
 // DockerContext is a typed representation of what we put in Context metadata
 type DockerContext struct {
 	Description       string       `json:",omitempty"`
 	StackOrchestrator Orchestrator `json:",omitempty"`
+	// Config overrides the configuration file when the context is used. It
+	// has the same keys as the configuration file.
+	Config map[string]interface{} `json:",omitempty"`
 }
 
 // GetDockerContext extracts metadata from stored context metadata
@@ -25,3 +28,25 @@ func GetDockerContext(storeMetadata store.ContextMetadata) (DockerContext, error
 	}
 	return res, nil
 }
+
+// ConfigFileWithContextOverrides returns the configuration to use with a
+// context, with its configuration overrides layered over configFile
+func ConfigFileWithContextOverrides(configFile *configfile.ConfigFile, s store.Store, contextName string) (*configfile.ConfigFile, error) {
+	var overrides map[string]interface{}
+	if contextName != "" {
+		ctxMeta, err := s.GetContextMetadata(contextName)
+		if err != nil {
+			return nil, err
+		}
+		dockerContext, err := GetDockerContext(ctxMeta)
+		if err != nil {
+			return nil, err
+		}
+		overrides = dockerContext.Config
+	}
+	result, err := configFile.WithOverrides(overrides)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to apply the configuration overrides of context %q", contextName)
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package context
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const defaultCheckTimeout = 5 * time.Second
+
+// checkedContext holds what is needed to check the endpoints of a context
+type checkedContext struct {
+	desc       *formatter.ClientContext
+	docker     *docker.Endpoint
+	kubernetes clientcmd.ClientConfig
+}
+
+// checkContexts connects to the endpoints of the contexts in parallel, and
+// stores the results in their descriptions
+func checkContexts(contexts []checkedContext, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, c := range contexts {
+		if c.docker != nil {
+			wg.Add(1)
+			go func(c checkedContext) {
+				defer wg.Done()
+				c.desc.DockerCheck = checkDockerEndpoint(c.docker, timeout)
+			}(c)
+		}
+		if c.kubernetes != nil {
+			wg.Add(1)
+			go func(c checkedContext) {
+				defer wg.Done()
+				c.desc.KubernetesCheck = checkKubernetesEndpoint(c.kubernetes, timeout)
+			}(c)
+		}
+	}
+	wg.Wait()
+}
+
+func checkDockerEndpoint(ep *docker.Endpoint, timeout time.Duration) *formatter.EndpointCheck {
+	check := &formatter.EndpointCheck{TLS: dockerEndpointTLS(ep)}
+	opts, err := ep.ClientOpts()
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	apiClient, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	ping, err := apiClient.Ping(ctx)
+	check.Latency = time.Since(start)
+	if err == nil {
+		apiClient.NegotiateAPIVersionPing(ping)
+		var version types.Version
+		if version, err = apiClient.ServerVersion(ctx); err == nil {
+			check.ServerVersion = version.Version
+		}
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return timedOutCheck(check, timeout)
+		}
+		return failedCheck(check, err)
+	}
+	check.Status = formatter.EndpointStatusOK
+	return check
+}
+
+// dockerEndpointTLS returns the TLS verification of a docker endpoint, before
+// connecting to it
+func dockerEndpointTLS(ep *docker.Endpoint) string {
+	if helper, err := connhelper.GetConnectionHelperWithOptions(ep.Host, connhelper.Options{PluginDirs: ep.PluginDirs}); err == nil && helper != nil {
+		// the connection is made by the helper
+		return formatter.EndpointTLSNone
+	}
+	switch {
+	case ep.SkipTLSVerify:
+		return formatter.EndpointTLSSkipped
+	case ep.TLSData != nil:
+		return formatter.EndpointTLSVerified
+	}
+	return formatter.EndpointTLSNone
+}
+
+func checkKubernetesEndpoint(cfg clientcmd.ClientConfig, timeout time.Duration) *formatter.EndpointCheck {
+	check := &formatter.EndpointCheck{TLS: formatter.EndpointTLSNone}
+	restConfig, err := cfg.ClientConfig()
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	switch {
+	case restConfig.Insecure:
+		check.TLS = formatter.EndpointTLSSkipped
+	case strings.HasPrefix(restConfig.Host, "https://"):
+		check.TLS = formatter.EndpointTLSVerified
+	}
+	restConfig.Timeout = timeout
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	start := time.Now()
+	version, err := discoveryClient.ServerVersion()
+	check.Latency = time.Since(start)
+	if err != nil {
+		if isTimeoutError(err) {
+			return timedOutCheck(check, timeout)
+		}
+		return failedCheck(check, err)
+	}
+	check.Status = formatter.EndpointStatusOK
+	check.ServerVersion = version.GitVersion
+	return check
+}
+
+func timedOutCheck(check *formatter.EndpointCheck, timeout time.Duration) *formatter.EndpointCheck {
+	check.Status = formatter.EndpointStatusTimeout
+	check.Error = "no response after " + timeout.String()
+	return check
+}
+
+// isTimeoutError returns whether err is caused by a timeout, of a context or
+// of a network operation. The kubernetes client does not take a context, and
+// returns the error of its HTTP client when it times out.
+func isTimeoutError(err error) bool {
+	err = errors.Cause(err)
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+func failedCheck(check *formatter.EndpointCheck, err error) *formatter.EndpointCheck {
+	check.Status = formatter.EndpointStatusUnreachable
+	check.Error = err.Error()
+	if check.TLS == formatter.EndpointTLSVerified && isTLSVerificationError(err) {
+		check.TLS = formatter.EndpointTLSFailed
+	}
+	return check
+}
+
+// isTLSVerificationError returns whether err is caused by the verification of
+// the certificates of the TLS connection, which the docker and kubernetes
+// clients only return as text
+func isTLSVerificationError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "x509: ") || strings.Contains(msg, "remote error: tls: ")
+}
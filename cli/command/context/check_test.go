@@ -0,0 +1,201 @@
+package context
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/kubernetes"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newTestDaemon(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(testDaemonHandler(delay))
+}
+
+// testDaemonHandler answers the requests made to check a docker endpoint
+func testDaemonHandler(delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		switch {
+		case r.URL.Path == "/_ping":
+			w.Header().Set("API-Version", "1.39")
+			fmt.Fprint(w, "OK")
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			fmt.Fprint(w, `{"Version": "18.09.1", "ApiVersion": "1.39"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func testDockerEndpoint(host string) *docker.Endpoint {
+	return &docker.Endpoint{
+		EndpointMeta: docker.EndpointMeta{
+			Host: host,
+		},
+	}
+}
+
+func TestCheckDockerEndpoint(t *testing.T) {
+	server := newTestDaemon(0)
+	defer server.Close()
+	host := strings.Replace(server.URL, "http://", "tcp://", 1)
+
+	check := checkDockerEndpoint(testDockerEndpoint(host), time.Second)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusOK))
+	assert.Check(t, is.Equal(check.ServerVersion, "18.09.1"))
+	assert.Check(t, is.Equal(check.TLS, formatter.EndpointTLSNone))
+	assert.Check(t, is.Equal(check.Error, ""))
+
+	server.Close()
+	check = checkDockerEndpoint(testDockerEndpoint(host), time.Second)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusUnreachable))
+	assert.Check(t, check.Error != "")
+}
+
+func TestCheckDockerEndpointTimeout(t *testing.T) {
+	server := newTestDaemon(300 * time.Millisecond)
+	defer server.Close()
+	host := strings.Replace(server.URL, "http://", "tcp://", 1)
+
+	check := checkDockerEndpoint(testDockerEndpoint(host), 50*time.Millisecond)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusTimeout))
+	assert.Check(t, is.Equal(check.Error, "no response after 50ms"))
+}
+
+func TestCheckDockerEndpointTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(testDaemonHandler(0))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	host := strings.Replace(server.URL, "https://", "tcp://", 1)
+
+	ep := testDockerEndpoint(host)
+	ep.SkipTLSVerify = true
+	check := checkDockerEndpoint(ep, time.Second)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusOK))
+	assert.Check(t, is.Equal(check.TLS, formatter.EndpointTLSSkipped))
+
+	// the server certificate is not signed by this CA
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	writeTestCerts(t, dir.Path())
+	ca, err := ioutil.ReadFile(dir.Join("ca.pem"))
+	assert.NilError(t, err)
+	ep = testDockerEndpoint(host)
+	ep.TLSData = &context.TLSData{CA: ca}
+	check = checkDockerEndpoint(ep, time.Second)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusUnreachable))
+	assert.Check(t, is.Equal(check.TLS, formatter.EndpointTLSFailed))
+}
+
+func TestCheckKubernetesEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"gitVersion": "v1.14.1"}`)
+	}))
+	defer server.Close()
+	ep := kubernetes.Endpoint{
+		EndpointMeta: kubernetes.EndpointMeta{
+			EndpointMetaBase: context.EndpointMetaBase{Host: server.URL},
+		},
+	}
+	check := checkKubernetesEndpoint(ep.KubernetesConfig(), time.Second)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusOK))
+	assert.Check(t, is.Equal(check.ServerVersion, "v1.14.1"))
+	assert.Check(t, is.Equal(check.TLS, formatter.EndpointTLSNone))
+}
+
+func TestCheckKubernetesEndpointTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		fmt.Fprint(w, `{"gitVersion": "v1.14.1"}`)
+	}))
+	defer server.Close()
+	ep := kubernetes.Endpoint{
+		EndpointMeta: kubernetes.EndpointMeta{
+			EndpointMetaBase: context.EndpointMetaBase{Host: server.URL},
+		},
+	}
+	check := checkKubernetesEndpoint(ep.KubernetesConfig(), 50*time.Millisecond)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusTimeout))
+	assert.Check(t, is.Equal(check.Error, "no response after 50ms"))
+
+	// errors are not reported as timeouts, however long they took
+	server.Close()
+	check = checkKubernetesEndpoint(ep.KubernetesConfig(), 50*time.Millisecond)
+	assert.Check(t, is.Equal(check.Status, formatter.EndpointStatusUnreachable))
+}
+
+func TestIsTimeoutError(t *testing.T) {
+	assert.Check(t, isTimeoutError(gocontext.DeadlineExceeded))
+	assert.Check(t, isTimeoutError(&url.Error{Op: "Get", URL: "https://k8s", Err: timeoutError{}}))
+	assert.Check(t, !isTimeoutError(errors.New("connection refused")))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestListCheckJSON(t *testing.T) {
+	server := newTestDaemon(0)
+	defer server.Close()
+	host := strings.Replace(server.URL, "http://", "tcp://", 1)
+
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, RunCreate(cli, &CreateOptions{
+		Name:   "test",
+		Docker: map[string]string{keyHost: host},
+	}))
+	cli.SetDockerEndpoint(*testDockerEndpoint(host))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormatKey, checkTimeout: defaultCheckTimeout}))
+	for _, line := range strings.Split(strings.TrimSpace(cli.OutBuffer().String()), "\n") {
+		var result map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &result))
+		_, ok := result["DockerStatus"]
+		assert.Check(t, !ok, line)
+	}
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormatKey, check: true, checkTimeout: defaultCheckTimeout}))
+	lines := strings.Split(strings.TrimSpace(cli.OutBuffer().String()), "\n")
+	assert.Assert(t, is.Len(lines, 2))
+	for _, line := range lines {
+		var result map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &result))
+		assert.Check(t, is.Equal(result["DockerStatus"], formatter.EndpointStatusOK), line)
+		assert.Check(t, is.Equal(result["DockerVersion"], "18.09.1"), line)
+		assert.Check(t, is.Equal(result["DockerTLS"], formatter.EndpointTLSNone), line)
+		assert.Check(t, is.Equal(result["KubernetesStatus"], ""), line)
+	}
+}
+
+func TestListCheckTimeout(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	err := runList(cli, &listOptions{check: true})
+	assert.Check(t, is.Error(err, "--check-timeout must be a positive duration"))
+}
@@ -21,6 +21,12 @@ type CreateOptions struct {
 	DefaultStackOrchestrator string
 	Docker                   map[string]string
 	Kubernetes               map[string]string
+	// ConfigOverrides is the path of a JSON file overriding the
+	// configuration file when the context is used
+	ConfigOverrides string
+	// PluginEndpoints are the endpoints of the types declared by CLI
+	// plugins, as key=value pairs by type
+	PluginEndpoints map[string]map[string]string
 }
 
 func longCreateDescription() string {
@@ -51,9 +57,11 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Name = args[0]
+			opts.PluginEndpoints = pluginEndpointsFromFlags(cmd.Flags())
 			return RunCreate(dockerCli, opts)
 		},
-		Long: longCreateDescription(),
+		Annotations: map[string]string{CommandAnnotationPluginEndpoints: "true"},
+		Long:        longCreateDescription(),
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Description, "description", "", "Description of the context")
@@ -63,6 +71,7 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 		"Default orchestrator for stack operations to use with this context (swarm|kubernetes|all)")
 	flags.StringToStringVar(&opts.Docker, "docker", nil, "set the docker endpoint")
 	flags.StringToStringVar(&opts.Kubernetes, "kubernetes", nil, "set the kubernetes endpoint")
+	flags.StringVar(&opts.ConfigOverrides, "config-overrides", "", "JSON file overriding the configuration file when the context is used")
 	return cmd
 }
 
@@ -76,11 +85,18 @@ func RunCreate(cli command.Cli, o *CreateOptions) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to parse default-stack-orchestrator")
 	}
+	var configOverrides map[string]interface{}
+	if o.ConfigOverrides != "" {
+		if configOverrides, err = loadConfigOverrides(o.ConfigOverrides); err != nil {
+			return err
+		}
+	}
 	contextMetadata := store.ContextMetadata{
 		Endpoints: make(map[string]interface{}),
 		Metadata: command.DockerContext{
 			Description:       o.Description,
 			StackOrchestrator: stackOrchestrator,
+			Config:            configOverrides,
 		},
 		Name: o.Name,
 	}
@@ -113,6 +129,12 @@ func RunCreate(cli command.Cli, o *CreateOptions) error {
 			contextTLSData.Endpoints[kubernetes.KubernetesEndpoint] = *kubernetesTLS
 		}
 	}
+	if err := validatePluginEndpoints(o.PluginEndpoints); err != nil {
+		return err
+	}
+	for endpointType, ep := range o.PluginEndpoints {
+		contextMetadata.Endpoints[endpointType] = ep
+	}
 	if err := validateEndpointsAndOrchestrator(contextMetadata); err != nil {
 		return err
 	}
@@ -7,12 +7,15 @@ import (
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper/ssh"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/kubernetes"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/env"
+	"gotest.tools/fs"
 )
 
 func makeFakeCli(t *testing.T, opts ...func(*test.FakeCli)) (*test.FakeCli, func()) {
@@ -73,6 +76,26 @@ func TestCreateInvalids(t *testing.T) {
 			},
 			expecterErr: `unable to parse docker host`,
 		},
+		{
+			options: CreateOptions{
+				Name: "ssh-options-without-ssh-host",
+				Docker: map[string]string{
+					keyHost:        "tcp://localhost:2376",
+					keySSHJumpHost: "bastion",
+				},
+			},
+			expecterErr: `ssh options can only be set for a docker endpoint with an ssh:// host`,
+		},
+		{
+			options: CreateOptions{
+				Name: "ssh-missing-identity-file",
+				Docker: map[string]string{
+					keyHost:            "ssh://me@server",
+					keySSHIdentityFile: "/no/such/key",
+				},
+			},
+			expecterErr: `ssh-identity-file: stat /no/such/key: no such file or directory`,
+		},
 		{
 			options: CreateOptions{
 				Name:                     "invalid-orchestrator",
@@ -138,6 +161,36 @@ func TestCreateOrchestratorEmpty(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestCreateSSHOptions(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("id_prod", "key"),
+		fs.WithFile("known_hosts", "server ssh-ed25519 AAAA"),
+	)
+	defer dir.Remove()
+
+	err := RunCreate(cli, &CreateOptions{
+		Name: "test",
+		Docker: map[string]string{
+			keyHost:              "ssh://me@server",
+			keySSHIdentityFile:   dir.Join("id_prod"),
+			keySSHKnownHostsFile: dir.Join("known_hosts"),
+			keySSHJumpHost:       "me@bastion:2222",
+		},
+	})
+	assert.NilError(t, err)
+	ctxMetadata, err := cli.ContextStore().GetContextMetadata("test")
+	assert.NilError(t, err)
+	dockerMeta, err := docker.EndpointFromContext(ctxMetadata)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(&ssh.Options{
+		IdentityFile:   dir.Join("id_prod"),
+		KnownHostsFile: dir.Join("known_hosts"),
+		JumpHost:       "me@bastion:2222",
+	}, dockerMeta.SSH))
+}
+
 func validateTestKubeEndpoint(t *testing.T, s store.Store, name string) {
 	t.Helper()
 	ctxMetadata, err := s.GetContextMetadata(name)
@@ -0,0 +1,242 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type execOptions struct {
+	contexts string
+	command  []string
+	parallel int
+}
+
+func newExecCommand(dockerCli command.Cli) *cobra.Command {
+	opts := &execOptions{}
+	cmd := &cobra.Command{
+		Use:   "exec [OPTIONS] CONTEXT[,CONTEXT...] COMMAND [ARG...]",
+		Short: "Run a docker command against one or more contexts",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.contexts = args[0]
+			opts.command = args[1:]
+			return runExec(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+	flags.IntVar(&opts.parallel, "parallel", 0, "Maximum number of contexts to run the command against at the same time (0 for no limit)")
+	return cmd
+}
+
+// execResult is the outcome of running the command against a context
+type execResult struct {
+	context  string
+	exitCode int
+}
+
+func runExec(dockerCli command.Cli, opts *execOptions) error {
+	if opts.parallel < 0 {
+		return errors.New("--parallel must be a positive number")
+	}
+	names, err := matchContexts(dockerCli.ContextStore(), opts.contexts)
+	if err != nil {
+		return err
+	}
+	binary, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "unable to find the docker binary")
+	}
+
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	parallel := opts.parallel
+	if parallel == 0 || parallel > len(names) {
+		parallel = len(names)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, parallel)
+		results = make([]execResult, len(names))
+	)
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			prefix := fmt.Sprintf("%-*s | ", width, name)
+			stdout := newPrefixWriter(dockerCli.Out(), &mu, prefix)
+			stderr := newPrefixWriter(dockerCli.Err(), &mu, prefix)
+			// the command is run as given, and only the configuration
+			// directory of the global options is passed on
+			args := append([]string{"--config", cliconfig.Dir(), "--context", name}, opts.command...)
+			c := exec.Command(binary, args...)
+			c.Stdout = stdout
+			c.Stderr = stderr
+			err := c.Run()
+			stdout.Flush()
+			if err != nil {
+				if exitErr, ok := err.(*exec.ExitError); !ok || exitStatus(exitErr) < 0 {
+					fmt.Fprintln(stderr, err)
+				}
+			}
+			stderr.Flush()
+			results[i] = execResult{context: name, exitCode: exitCode(err)}
+		}(i, name)
+	}
+	wg.Wait()
+	return execStatus(results)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitStatus(exitErr) > 0 {
+		return exitStatus(exitErr)
+	}
+	return 1
+}
+
+// exitStatus returns the exit status of a command which exited, or -1 if it
+// was killed by a signal
+func exitStatus(exitErr *exec.ExitError) int {
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+		return status.ExitStatus()
+	}
+	// the exit status is not available on this platform
+	return 1
+}
+
+// execStatus combines the results of the command into a single status. The
+// exit code is the highest exit code of the command across all contexts.
+func execStatus(results []execResult) error {
+	var (
+		failed []string
+		code   int
+	)
+	for _, result := range results {
+		if result.exitCode == 0 {
+			continue
+		}
+		failed = append(failed, result.context)
+		if result.exitCode > code {
+			code = result.exitCode
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return cli.StatusError{
+		StatusCode: code,
+		Status:     fmt.Sprintf("command failed on %d of %d contexts: %s", len(failed), len(results), strings.Join(failed, ", ")),
+	}
+}
+
+// matchContexts returns the sorted names of the contexts matching a comma
+// separated list of context names and glob patterns, such as "prod-*".
+func matchContexts(s store.Store, patterns string) ([]string, error) {
+	contexts, err := s.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		names   []string
+		matched = map[string]bool{}
+	)
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		found := false
+		for _, c := range contexts {
+			ok, err := path.Match(pattern, c.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid context pattern %q", pattern)
+			}
+			if !ok {
+				continue
+			}
+			found = true
+			if !matched[c.Name] {
+				matched[c.Name] = true
+				names = append(names, c.Name)
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("no context matches %q", pattern)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no context specified")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// prefixWriter writes every line written to it to out, prefixed with prefix.
+// Lines are written whole while holding mu, so that the output of several
+// writers sharing the same lock is not interleaved.
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    bytes.Buffer
+}
+
+func newPrefixWriter(out io.Writer, mu *sync.Mutex, prefix string) *prefixWriter {
+	return &prefixWriter{out: out, mu: mu, prefix: prefix}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf.Next(i + 1)); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the last line if it is not terminated by a newline
+func (w *prefixWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := append(w.buf.Next(w.buf.Len()), '\n')
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
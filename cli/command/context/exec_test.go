@@ -0,0 +1,81 @@
+package context
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestMatchContexts(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	for _, name := range []string{"prod-eu", "prod-us", "staging"} {
+		assert.NilError(t, cli.ContextStore().CreateOrUpdateContext(store.ContextMetadata{Name: name}))
+	}
+
+	testCases := []struct {
+		patterns    string
+		expected    []string
+		expectedErr string
+	}{
+		{patterns: "staging", expected: []string{"staging"}},
+		{patterns: "prod-*", expected: []string{"prod-eu", "prod-us"}},
+		{patterns: "staging,prod-*,prod-us", expected: []string{"prod-eu", "prod-us", "staging"}},
+		{patterns: "*", expected: []string{"prod-eu", "prod-us", "staging"}},
+		{patterns: "dev-*", expectedErr: `no context matches "dev-*"`},
+		{patterns: "prod-[", expectedErr: `invalid context pattern "prod-["`},
+		{patterns: ",", expectedErr: "no context specified"},
+	}
+	for _, tc := range testCases {
+		names, err := matchContexts(cli.ContextStore(), tc.patterns)
+		if tc.expectedErr != "" {
+			assert.ErrorContains(t, err, tc.expectedErr)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, names))
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var (
+		out bytes.Buffer
+		mu  sync.Mutex
+	)
+	w := newPrefixWriter(&out, &mu, "prod | ")
+	_, err := w.Write([]byte("first line\nsec"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("prod | first line\n", out.String()))
+	_, err = w.Write([]byte("ond line\nlast"))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Flush())
+	assert.Check(t, is.Equal("prod | first line\nprod | second line\nprod | last\n", out.String()))
+}
+
+func TestExecStatus(t *testing.T) {
+	assert.NilError(t, execStatus([]execResult{{context: "a"}, {context: "b"}}))
+
+	err := execStatus([]execResult{{context: "a", exitCode: 1}, {context: "b"}, {context: "c", exitCode: 125}})
+	assert.Check(t, is.DeepEqual(cli.StatusError{
+		StatusCode: 125,
+		Status:     "command failed on 2 of 3 contexts: a, c",
+	}, err))
+}
+
+func TestExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test runs sh")
+	}
+	assert.Check(t, is.Equal(0, exitCode(exec.Command("sh", "-c", "exit 0").Run())))
+	assert.Check(t, is.Equal(3, exitCode(exec.Command("sh", "-c", "exit 3").Run())))
+	// killed by a signal
+	assert.Check(t, is.Equal(1, exitCode(exec.Command("sh", "-c", "kill -9 $$").Run())))
+	assert.Check(t, is.Equal(1, exitCode(exec.Command("does-not-exist").Run())))
+}
@@ -2,14 +2,27 @@ package context
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/opts"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
 )
 
 func TestExportImportWithFile(t *testing.T) {
@@ -108,3 +121,183 @@ func TestExportExistingFile(t *testing.T) {
 	err = RunExport(cli, &ExportOptions{ContextName: "test", Dest: contextFile})
 	assert.Assert(t, os.IsExist(err))
 }
+
+// writeTestCerts writes a self-signed certificate and its key to dir, as
+// ca.pem, cert.pem and key.pem
+func writeTestCerts(t *testing.T, dir string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyData, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "ca.pem"), certPEM, 0600))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "cert.pem"), certPEM, 0600))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData}), 0600))
+}
+
+func getTestDockerEndpoint(t *testing.T, cli command.Cli, name string) docker.Endpoint {
+	t.Helper()
+	ctxMeta, err := cli.ContextStore().GetContextMetadata(name)
+	assert.NilError(t, err)
+	epMeta, err := docker.EndpointFromContext(ctxMeta)
+	assert.NilError(t, err)
+	ep, err := docker.WithTLSData(cli.ContextStore(), name, epMeta)
+	assert.NilError(t, err)
+	return ep
+}
+
+func TestExportImportEnv(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	writeTestCerts(t, dir.Path())
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, RunCreate(cli, &CreateOptions{
+		Name: "test",
+		Docker: map[string]string{
+			keyHost: "tcp://127.0.0.1:2376",
+			keyCA:   dir.Join("ca.pem"),
+			keyCert: dir.Join("cert.pem"),
+			keyKey:  dir.Join("key.pem"),
+		},
+	}))
+	cli.ErrBuffer().Reset()
+	envFile := dir.Join("test.env")
+	certDir := dir.Join("certs")
+	assert.NilError(t, RunExport(cli, &ExportOptions{
+		ContextName: "test",
+		Dest:        envFile,
+		Env:         true,
+		CertDir:     certDir,
+	}))
+	assert.Equal(t, cli.ErrBuffer().String(), fmt.Sprintf("Written file %q\nWritten TLS material to %q\n", envFile, certDir))
+	data, err := ioutil.ReadFile(envFile)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), fmt.Sprintf(`export DOCKER_HOST="tcp://127.0.0.1:2376"
+export DOCKER_TLS_VERIFY="1"
+export DOCKER_CERT_PATH="%s"
+`, certDir))
+	for _, f := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		expected, err := ioutil.ReadFile(dir.Join(f))
+		assert.NilError(t, err)
+		actual, err := ioutil.ReadFile(filepath.Join(certDir, f))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, expected, actual)
+	}
+
+	assert.NilError(t, RunImport(cli, "test2", envFile))
+	ep1 := getTestDockerEndpoint(t, cli, "test")
+	ep2 := getTestDockerEndpoint(t, cli, "test2")
+	assert.DeepEqual(t, ep1.EndpointMeta, ep2.EndpointMeta)
+	assert.DeepEqual(t, ep1.TLSData, ep2.TLSData)
+}
+
+func TestExportEnvSSHOptions(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, RunCreate(cli, &CreateOptions{
+		Name: "test",
+		Docker: map[string]string{
+			keyHost:        "ssh://me@server",
+			keySSHJumpHost: "bastion",
+		},
+	}))
+	err := RunExport(cli, &ExportOptions{ContextName: "test", Dest: "-", Env: true})
+	assert.ErrorContains(t, err, "ssh options of the endpoint cannot be set with environment variables")
+}
+
+func TestExportEnvAndKubeconfig(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	createTestContextWithKube(t, cli)
+	err := RunExport(cli, &ExportOptions{ContextName: "test", Dest: "-", Env: true, Kubeconfig: true})
+	assert.ErrorContains(t, err, "--kubeconfig and --env cannot be used together")
+}
+
+func TestImportMachineEnv(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	// output of "docker-machine env" for a machine without TLS
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(bytes.NewBufferString(`export DOCKER_HOST="tcp://192.168.99.100:2375"
+export DOCKER_MACHINE_NAME="default"
+# Run this command to configure your shell:
+# eval $(docker-machine env)
+`))))
+	assert.NilError(t, RunImport(cli, "machine", "-"))
+	ep := getTestDockerEndpoint(t, cli, "machine")
+	assert.Equal(t, ep.Host, "tcp://192.168.99.100:2375")
+	assert.Check(t, !ep.SkipTLSVerify)
+	assert.Check(t, is.Nil(ep.TLSData))
+}
+
+func TestImportMachineDir(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("config.json", `{"Driver": {"IPAddress": "192.168.99.100", "MachineName": "default"}}`))
+	defer dir.Remove()
+	writeTestCerts(t, dir.Path())
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, RunImport(cli, "machine", dir.Path()))
+	ep := getTestDockerEndpoint(t, cli, "machine")
+	assert.Equal(t, ep.Host, "tcp://192.168.99.100:2376")
+	assert.Check(t, !ep.SkipTLSVerify)
+	assert.Assert(t, ep.TLSData != nil)
+	ca, err := ioutil.ReadFile(dir.Join("ca.pem"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ep.TLSData.CA, ca)
+}
+
+func TestImportKubeconfig(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, RunImport(cli, "test", "./testdata/test-kubeconfig"))
+	validateTestKubeEndpoint(t, cli.ContextStore(), "test")
+	ctxMeta, err := cli.ContextStore().GetContextMetadata("test")
+	assert.NilError(t, err)
+	assert.Equal(t, ctxMeta.Metadata.(command.DockerContext).StackOrchestrator, command.OrchestratorKubernetes)
+	assert.Equal(t, getTestDockerEndpoint(t, cli, "test").Host, opts.DefaultHost)
+
+	data, err := ioutil.ReadFile("./testdata/test-kubeconfig")
+	assert.NilError(t, err)
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(bytes.NewBuffer(data))))
+	assert.NilError(t, RunImport(cli, "test2", "-"))
+	validateTestKubeEndpoint(t, cli.ContextStore(), "test2")
+}
+
+func TestImportUnknownFormat(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(bytes.NewBufferString("not a context"))))
+	err := RunImport(cli, "test", "-")
+	assert.ErrorContains(t, err, "unable to detect the format of the context to import")
+	_, err = cli.ContextStore().GetContextMetadata("test")
+	assert.Assert(t, err != nil)
+}
+
+func TestParseEnvFile(t *testing.T) {
+	env := parseEnvFile([]byte(`# comment
+export DOCKER_HOST="tcp://host:2376"
+DOCKER_TLS_VERIFY=1
+export DOCKER_CERT_PATH='/path with spaces'
+export QUOTED="a \"b\" \$c \\d"
+not an assignment
+`))
+	assert.DeepEqual(t, env, map[string]string{
+		"DOCKER_HOST":       "tcp://host:2376",
+		"DOCKER_TLS_VERIFY": "1",
+		"DOCKER_CERT_PATH":  "/path with spaces",
+		"QUOTED":            `a "b" $c \d`,
+	})
+	assert.Equal(t, quoteEnvValue(`a "b" $c \d`), `"a \"b\" \$c \\d"`)
+}
@@ -2,15 +2,19 @@ package context
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/kubernetes"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -18,6 +22,8 @@ import (
 // ExportOptions are the options used for exporting a context
 type ExportOptions struct {
 	Kubeconfig  bool
+	Env         bool
+	CertDir     string
 	ContextName string
 	Dest        string
 }
@@ -26,7 +32,7 @@ func newExportCommand(dockerCli command.Cli) *cobra.Command {
 	opts := &ExportOptions{}
 	cmd := &cobra.Command{
 		Use:   "export [OPTIONS] CONTEXT [FILE|-]",
-		Short: "Export a context to a tar or kubeconfig file",
+		Short: "Export a context to a tar, kubeconfig or environment file",
 		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.ContextName = args[0]
@@ -34,9 +40,12 @@ func newExportCommand(dockerCli command.Cli) *cobra.Command {
 				opts.Dest = args[1]
 			} else {
 				opts.Dest = opts.ContextName
-				if opts.Kubeconfig {
+				switch {
+				case opts.Kubeconfig:
 					opts.Dest += ".kubeconfig"
-				} else {
+				case opts.Env:
+					opts.Dest += ".env"
+				default:
 					opts.Dest += ".dockercontext"
 				}
 			}
@@ -46,6 +55,8 @@ func newExportCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.Kubeconfig, "kubeconfig", false, "Export as a kubeconfig file")
+	flags.BoolVar(&opts.Env, "env", false, "Export the docker endpoint as a file setting DOCKER_* environment variables")
+	flags.StringVar(&opts.CertDir, "cert-dir", "", "Directory to write the TLS material of the docker endpoint to with --env (default \"CONTEXT-certs\")")
 	return cmd
 }
 
@@ -84,6 +95,12 @@ func RunExport(dockerCli command.Cli, opts *ExportOptions) error {
 	if err != nil {
 		return err
 	}
+	if opts.Kubeconfig && opts.Env {
+		return errors.New("--kubeconfig and --env cannot be used together")
+	}
+	if opts.Env {
+		return exportEnv(dockerCli, opts, ctxMeta)
+	}
 	if !opts.Kubeconfig {
 		reader := store.Export(opts.ContextName, dockerCli.ContextStore())
 		defer reader.Close()
@@ -108,3 +125,72 @@ func RunExport(dockerCli command.Cli, opts *ExportOptions) error {
 	}
 	return writeTo(dockerCli, bytes.NewBuffer(data), opts.Dest)
 }
+
+// exportEnv writes the docker endpoint of a context as a POSIX shell script
+// setting DOCKER_* environment variables, like "docker-machine env" does.
+// The TLS material of the endpoint is written to opts.CertDir.
+func exportEnv(dockerCli command.Cli, opts *ExportOptions, ctxMeta store.ContextMetadata) error {
+	epMeta, err := docker.EndpointFromContext(ctxMeta)
+	if err != nil {
+		return err
+	}
+	ep, err := docker.WithTLSData(dockerCli.ContextStore(), opts.ContextName, epMeta)
+	if err != nil {
+		return err
+	}
+	var certDir string
+	if ep.TLSData != nil {
+		certDir = opts.CertDir
+		if certDir == "" {
+			certDir = opts.ContextName + "-certs"
+		}
+		if certDir, err = filepath.Abs(certDir); err != nil {
+			return err
+		}
+	}
+	env, err := ep.Environment(certDir)
+	if err != nil {
+		return errors.Wrapf(err, "unable to export context %q as environment variables", opts.ContextName)
+	}
+	buf := bytes.NewBuffer(nil)
+	for _, v := range env {
+		kv := strings.SplitN(v, "=", 2)
+		fmt.Fprintf(buf, "export %s=%s\n", kv[0], quoteEnvValue(kv[1]))
+	}
+	if certDir != "" {
+		if err := writeTLSFiles(certDir, ep.TLSFiles()); err != nil {
+			return err
+		}
+	}
+	if err := writeTo(dockerCli, buf, opts.Dest); err != nil {
+		if certDir != "" {
+			os.RemoveAll(certDir)
+		}
+		return err
+	}
+	if certDir != "" {
+		fmt.Fprintf(dockerCli.Err(), "Written TLS material to %q\n", certDir)
+	}
+	return nil
+}
+
+var envValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+
+// quoteEnvValue double-quotes a value for a POSIX shell
+func quoteEnvValue(value string) string {
+	return `"` + envValueEscaper.Replace(value) + `"`
+}
+
+// writeTLSFiles writes TLS material to a new directory, only readable by the
+// current user
+func writeTLSFiles(dir string, files map[string][]byte) error {
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,20 +1,36 @@
 package context
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/kubernetes"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func newImportCommand(dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "import CONTEXT FILE|-",
-		Short: "Import a context from a tar file",
+		Use:   "import CONTEXT FILE|DIRECTORY|-",
+		Short: "Import a context from a tar, kubeconfig or environment file, or a docker-machine directory",
 		Args:  cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return RunImport(dockerCli, args[0], args[1])
@@ -23,15 +39,34 @@ func newImportCommand(dockerCli command.Cli) *cobra.Command {
 	return cmd
 }
 
-// RunImport imports a Docker context
+// RunImport imports a Docker context. The format of the source is detected
+// from its content: it can be a context exported as a tar file, a kubeconfig
+// file, a file setting DOCKER_* environment variables, or a docker-machine
+// machine directory.
 func RunImport(dockerCli command.Cli, name string, source string) error {
 	if err := checkContextNameForCreation(dockerCli.ContextStore(), name); err != nil {
 		return err
 	}
+	if err := importContext(dockerCli, name, source); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), name)
+	fmt.Fprintf(dockerCli.Err(), "Successfully imported context %q\n", name)
+	return nil
+}
+
+func importContext(dockerCli command.Cli, name string, source string) error {
 	var reader io.Reader
 	if source == "-" {
 		reader = dockerCli.In()
 	} else {
+		fi, err := os.Stat(source)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return importMachineDir(dockerCli, name, source)
+		}
 		f, err := os.Open(source)
 		if err != nil {
 			return err
@@ -40,10 +75,177 @@ func RunImport(dockerCli command.Cli, name string, source string) error {
 		reader = f
 	}
 
-	if err := store.Import(name, dockerCli.ContextStore(), reader); err != nil {
+	r := bufio.NewReader(reader)
+	if isTar(r) {
+		return store.Import(name, dockerCli.ContextStore(), r)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
 		return err
 	}
-	fmt.Fprintln(dockerCli.Out(), name)
-	fmt.Fprintf(dockerCli.Err(), "Successfully imported context %q\n", name)
-	return nil
+	if env := parseEnvFile(data); env[docker.EnvHost] != "" {
+		return importEnvFile(dockerCli, name, env)
+	}
+	if kubeConfig, err := clientcmd.Load(data); err == nil && len(kubeConfig.Clusters) > 0 {
+		return importKubeconfig(dockerCli, name, source, data)
+	}
+	return errors.New("unable to detect the format of the context to import: expected a tar file exported by \"docker context export\", " +
+		"a kubeconfig file, a file setting DOCKER_HOST or a docker-machine machine directory")
+}
+
+// isTar returns whether r starts with a tar header
+func isTar(r *bufio.Reader) bool {
+	const magicOffset = 257
+	header, err := r.Peek(magicOffset + 5)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(header[magicOffset:], []byte("ustar"))
+}
+
+var envAssignment = regexp.MustCompile(`^(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+
+// parseEnvFile parses the variables set by a POSIX shell script, such as the
+// output of "docker-machine env" or "docker context export --env". Lines which
+// are not variable assignments are ignored.
+func parseEnvFile(data []byte) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		m := envAssignment.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		env[m[1]] = unquoteEnvValue(strings.TrimSuffix(m[2], ";"))
+	}
+	return env
+}
+
+func unquoteEnvValue(value string) string {
+	switch {
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		value = value[1 : len(value)-1]
+		var b strings.Builder
+		for i := 0; i < len(value); i++ {
+			if value[i] == '\\' && i+1 < len(value) && strings.IndexByte("\\\"$`", value[i+1]) >= 0 {
+				i++
+			}
+			b.WriteByte(value[i])
+		}
+		return b.String()
+	}
+	return value
+}
+
+func importEnvFile(dockerCli command.Cli, name string, env map[string]string) error {
+	ep, err := docker.FromEnvironment(env, cliconfig.Dir())
+	if err != nil {
+		return err
+	}
+	return createImportedContext(dockerCli, name, ep, nil)
+}
+
+// machineConfig is the part of the configuration of a docker-machine machine
+// needed to connect to its engine
+type machineConfig struct {
+	Driver struct {
+		IPAddress  string
+		EnginePort int
+	}
+}
+
+const defaultMachineEnginePort = 2376
+
+// importMachineDir imports the engine of a docker-machine machine, from its
+// directory holding its configuration and TLS material
+func importMachineDir(dockerCli command.Cli, name string, dir string) error {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return errors.Wrapf(err, "%s is not a docker-machine machine directory", dir)
+	}
+	var cfg machineConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return errors.Wrapf(err, "unable to parse the docker-machine configuration in %s", dir)
+	}
+	if cfg.Driver.IPAddress == "" {
+		return errors.Errorf("the docker-machine configuration in %s has no IP address", dir)
+	}
+	port := cfg.Driver.EnginePort
+	if port == 0 {
+		port = defaultMachineEnginePort
+	}
+	ep, err := docker.FromEnvironment(map[string]string{
+		docker.EnvHost:      "tcp://" + net.JoinHostPort(cfg.Driver.IPAddress, strconv.Itoa(port)),
+		docker.EnvTLSVerify: "1",
+		docker.EnvCertPath:  dir,
+	}, "")
+	if err != nil {
+		return err
+	}
+	return createImportedContext(dockerCli, name, ep, nil)
+}
+
+// importKubeconfig imports the current context of a kubeconfig file. The
+// docker endpoint of the imported context is the default local engine.
+func importKubeconfig(dockerCli command.Cli, name string, source string, data []byte) error {
+	var (
+		ep  kubernetes.Endpoint
+		err error
+	)
+	if source == "-" {
+		ep, err = kubernetes.FromKubeConfigData(data)
+	} else {
+		// loading the file resolves relative paths from its directory
+		ep, err = kubernetes.FromKubeConfig(source, "", "")
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to load kubeconfig")
+	}
+	dockerEP := docker.Endpoint{
+		EndpointMeta: docker.EndpointMeta{
+			Host: opts.DefaultHost,
+		},
+	}
+	return createImportedContext(dockerCli, name, dockerEP, &ep)
+}
+
+func createImportedContext(dockerCli command.Cli, name string, dockerEP docker.Endpoint, kubernetesEP *kubernetes.Endpoint) error {
+	dockerEP.PluginDirs = cliconfig.PluginDirs(dockerCli.ConfigFile())
+	// try to resolve a docker client, validating the endpoint
+	clientOpts, err := dockerEP.ClientOpts()
+	if err != nil {
+		return errors.Wrap(err, "invalid docker endpoint")
+	}
+	if _, err := client.NewClientWithOpts(clientOpts...); err != nil {
+		return errors.Wrap(err, "invalid docker endpoint")
+	}
+
+	contextMetadata := store.ContextMetadata{
+		Endpoints: map[string]interface{}{
+			docker.DockerEndpoint: dockerEP.EndpointMeta,
+		},
+		Metadata: command.DockerContext{},
+		Name:     name,
+	}
+	contextTLSData := store.ContextTLSData{
+		Endpoints: make(map[string]store.EndpointTLSData),
+	}
+	if dockerTLS := dockerEP.TLSData.ToStoreTLSData(); dockerTLS != nil {
+		contextTLSData.Endpoints[docker.DockerEndpoint] = *dockerTLS
+	}
+	if kubernetesEP != nil {
+		contextMetadata.Endpoints[kubernetes.KubernetesEndpoint] = kubernetesEP.EndpointMeta
+		contextMetadata.Metadata = command.DockerContext{
+			StackOrchestrator: command.OrchestratorKubernetes,
+		}
+		if kubernetesTLS := kubernetesEP.TLSData.ToStoreTLSData(); kubernetesTLS != nil {
+			contextTLSData.Endpoints[kubernetes.KubernetesEndpoint] = *kubernetesTLS
+		}
+	}
+	s := dockerCli.ContextStore()
+	if err := s.CreateOrUpdateContext(contextMetadata); err != nil {
+		return err
+	}
+	return s.ResetContextTLSMaterial(name, &contextTLSData)
 }
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/docker/cli/cli/command"
@@ -51,10 +52,15 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 		if err != nil {
 			return nil, nil, err
 		}
+		effectiveConfig, err := getEffectiveConfig(dockerCli, ref)
+		if err != nil {
+			return nil, nil, err
+		}
 		return contextWithTLSListing{
 			ContextMetadata: c,
 			TLSMaterial:     tlsListing,
 			Storage:         dockerCli.ContextStore().GetContextStorageInfo(ref),
+			EffectiveConfig: effectiveConfig,
 		}, nil, nil
 	}
 	return inspect.Inspect(dockerCli.Out(), opts.refs, opts.format, getRefFunc)
@@ -64,4 +70,27 @@ type contextWithTLSListing struct {
 	store.ContextMetadata
 	TLSMaterial map[string]store.EndpointFiles
 	Storage     store.ContextStorageInfo
+	// EffectiveConfig is the configuration used with the context, without
+	// credentials
+	EffectiveConfig map[string]interface{}
+}
+
+// getEffectiveConfig returns the configuration used with a context, that is
+// the configuration file with the overrides of the context, without the
+// credentials it holds
+func getEffectiveConfig(dockerCli command.Cli, name string) (map[string]interface{}, error) {
+	configFile, err := command.ConfigFileWithContextOverrides(dockerCli.ConfigFile(), dockerCli.ContextStore(), name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(configFile)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	delete(config, "auths")
+	return config, nil
 }
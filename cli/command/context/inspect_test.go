@@ -4,7 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
 	"gotest.tools/assert"
+	"gotest.tools/fs"
 	"gotest.tools/golden"
 )
 
@@ -22,3 +25,41 @@ func TestInspect(t *testing.T) {
 	expected = strings.Replace(expected, "<TLS_PATH>", strings.Replace(si.TLSPath, `\`, `\\`, -1), 1)
 	assert.Equal(t, cli.OutBuffer().String(), expected)
 }
+
+func TestInspectConfigOverrides(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("overrides.json", `{"psFormat": "table {{.Names}}", "HttpHeaders": {"X-Env": "prod"}}`),
+		fs.WithFile("empty.json", `{}`),
+		fs.WithFile("invalid.json", `{"auths": {}}`))
+	defer dir.Remove()
+	configFile := configfile.New("config.json")
+	configFile.PsFormat = "table {{.ID}}"
+	configFile.ImagesFormat = "table {{.ID}}"
+	configFile.AuthConfigs["example.com"] = types.AuthConfig{Username: "user", Password: "pass"}
+	cli, cleanup := makeFakeCli(t, withCliConfig(configFile))
+	defer cleanup()
+	assert.NilError(t, RunCreate(cli, &CreateOptions{
+		Name:            "prod",
+		Docker:          map[string]string{},
+		ConfigOverrides: dir.Join("overrides.json"),
+	}))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runInspect(cli, inspectOptions{
+		refs:   []string{"prod"},
+		format: "{{json .Metadata.Config}} {{json .EffectiveConfig}}",
+	}))
+	assert.Equal(t, cli.OutBuffer().String(), `{"HttpHeaders":{"X-Env":"prod"},"psFormat":"table {{.Names}}"} `+
+		`{"HttpHeaders":{"X-Env":"prod"},"imagesFormat":"table {{.ID}}","psFormat":"table {{.Names}}"}`+"\n")
+
+	err := RunUpdate(cli, &UpdateOptions{Name: "prod", ConfigOverrides: dir.Join("invalid.json")})
+	assert.ErrorContains(t, err, `configuration keys cannot be overridden: ["auths"]`)
+
+	assert.NilError(t, RunUpdate(cli, &UpdateOptions{Name: "prod", ConfigOverrides: dir.Join("empty.json")}))
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runInspect(cli, inspectOptions{
+		refs:   []string{"prod"},
+		format: "{{json .EffectiveConfig}}",
+	}))
+	assert.Equal(t, cli.OutBuffer().String(), `{"imagesFormat":"table {{.ID}}","psFormat":"table {{.ID}}"}`+"\n")
+}
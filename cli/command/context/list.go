@@ -3,20 +3,26 @@ package context
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	cliconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/context/docker"
 	kubecontext "github.com/docker/cli/cli/context/kubernetes"
 	"github.com/docker/cli/kubernetes"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"vbom.ml/util/sortorder"
 )
 
 type listOptions struct {
-	format string
-	quiet  bool
+	format       string
+	sort         string
+	quiet        bool
+	check        bool
+	checkTimeout time.Duration
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -33,7 +39,10 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVar(&opts.format, "format", "", "Pretty-print contexts using a Go template")
+	formatter.AddSortFlag(flags, &opts.sort)
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show context names")
+	flags.BoolVar(&opts.check, "check", false, "Connect to the endpoints of the contexts and show their status")
+	flags.DurationVar(&opts.checkTimeout, "check-timeout", defaultCheckTimeout, "Timeout of the connection to each endpoint with --check")
 	return cmd
 }
 
@@ -41,12 +50,20 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 	if opts.format == "" {
 		opts.format = formatter.TableFormatKey
 	}
+	if opts.check && opts.checkTimeout <= 0 {
+		return errors.New("--check-timeout must be a positive duration")
+	}
+	// names are all that is shown with --quiet
+	check := opts.check && !opts.quiet
 	curContext := dockerCli.CurrentContext()
 	contextMap, err := dockerCli.ContextStore().ListContexts()
 	if err != nil {
 		return err
 	}
-	var contexts []*formatter.ClientContext
+	var (
+		contexts []*formatter.ClientContext
+		checked  []checkedContext
+	)
 	for _, rawMeta := range contextMap {
 		meta, err := command.GetDockerContext(rawMeta)
 		if err != nil {
@@ -70,6 +87,9 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 			KubernetesEndpoint: kubEndpointText,
 		}
 		contexts = append(contexts, &desc)
+		if check {
+			checked = append(checked, newCheckedContext(dockerCli, rawMeta.Name, &desc, dockerEndpoint, kubernetesEndpoint))
+		}
 	}
 	if !opts.quiet {
 		desc := &formatter.ClientContext{
@@ -91,19 +111,58 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 			desc.StackOrchestrator = string(orchestrator)
 			desc.DockerEndpoint = dockerCli.DockerEndpoint().Host
 			desc.KubernetesEndpoint = kubEndpointText
+			if check {
+				dockerEndpoint := dockerCli.DockerEndpoint()
+				c := checkedContext{desc: desc, docker: &dockerEndpoint}
+				if kubEndpointText != "" {
+					c.kubernetes = kubeconfig
+				}
+				checked = append(checked, c)
+			}
 		}
 		contexts = append(contexts, desc)
 	}
+	if check {
+		checkContexts(checked, opts.checkTimeout)
+	}
 	sort.Slice(contexts, func(i, j int) bool {
 		return sortorder.NaturalLess(contexts[i].Name, contexts[j].Name)
 	})
 	return format(dockerCli, opts, contexts)
 }
 
+// newCheckedContext returns what is needed to check the endpoints of a stored
+// context. Endpoints whose TLS material cannot be loaded are reported as
+// unreachable without being checked.
+func newCheckedContext(dockerCli command.Cli, name string, desc *formatter.ClientContext, dockerMeta docker.EndpointMeta, kubernetesMeta *kubecontext.EndpointMeta) checkedContext {
+	c := checkedContext{desc: desc}
+	dockerEndpoint, err := docker.WithTLSData(dockerCli.ContextStore(), name, dockerMeta)
+	if err != nil {
+		desc.DockerCheck = failedCheck(&formatter.EndpointCheck{}, err)
+	} else {
+		dockerEndpoint.PluginDirs = cliconfig.PluginDirs(dockerCli.ConfigFile())
+		c.docker = &dockerEndpoint
+	}
+	if kubernetesMeta != nil {
+		kubernetesEndpoint, err := kubernetesMeta.WithTLSData(dockerCli.ContextStore(), name)
+		if err != nil {
+			desc.KubernetesCheck = failedCheck(&formatter.EndpointCheck{}, err)
+		} else {
+			c.kubernetes = kubernetesEndpoint.KubernetesConfig()
+		}
+	}
+	return c
+}
+
 func format(dockerCli command.Cli, opts *listOptions, contexts []*formatter.ClientContext) error {
+	newFormat := formatter.NewClientContextFormat
+	if opts.check {
+		newFormat = formatter.NewClientContextCheckFormat
+	}
 	contextCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: formatter.NewClientContextFormat(opts.format, opts.quiet),
+		Format: newFormat(opts.format, opts.quiet),
+		Sort:   opts.sort,
 	}
 	return formatter.ClientContextWrite(contextCtx, contexts)
 }
@@ -1,13 +1,19 @@
 package context
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli/command"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper/ssh"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/kubernetes"
@@ -27,6 +33,11 @@ const (
 	keyKubeconfig    = "config-file"
 	keyKubecontext   = "context-override"
 	keyKubenamespace = "namespace-override"
+
+	keySSHIdentityFile   = "ssh-identity-file"
+	keySSHConfigFile     = "ssh-config-file"
+	keySSHKnownHostsFile = "ssh-known-hosts-file"
+	keySSHJumpHost       = "ssh-jump-host"
 )
 
 type configKeyDescription struct {
@@ -42,6 +53,11 @@ var (
 		keyCert:          {},
 		keyKey:           {},
 		keySkipTLSVerify: {},
+
+		keySSHIdentityFile:   {},
+		keySSHConfigFile:     {},
+		keySSHKnownHostsFile: {},
+		keySSHJumpHost:       {},
 	}
 	allowedKubernetesConfigKeys = map[string]struct{}{
 		keyFromCurrent:   {},
@@ -74,6 +90,22 @@ var (
 			name:        keySkipTLSVerify,
 			description: "Skip TLS certificate validation",
 		},
+		{
+			name:        keySSHIdentityFile,
+			description: "Path to the ssh private key to authenticate with",
+		},
+		{
+			name:        keySSHConfigFile,
+			description: "Path to the ssh configuration file",
+		},
+		{
+			name:        keySSHKnownHostsFile,
+			description: "Path to the known_hosts file to check the host key against",
+		},
+		{
+			name:        keySSHJumpHost,
+			description: "ssh hosts to connect through (ProxyJump)",
+		},
 	}
 	kubernetesConfigKeysDescriptions = []configKeyDescription{
 		{
@@ -136,12 +168,18 @@ func getDockerEndpoint(dockerCli command.Cli, config map[string]string) (docker.
 	if err != nil {
 		return docker.Endpoint{}, err
 	}
+	sshOpts, err := getSSHOptions(config)
+	if err != nil {
+		return docker.Endpoint{}, err
+	}
 	ep := docker.Endpoint{
 		EndpointMeta: docker.EndpointMeta{
 			Host:          config[keyHost],
 			SkipTLSVerify: skipTLSVerify,
+			SSH:           sshOpts,
 		},
-		TLSData: tlsData,
+		TLSData:    tlsData,
+		PluginDirs: cliconfig.PluginDirs(dockerCli.ConfigFile()),
 	}
 	// try to resolve a docker client, validating the configuration
 	opts, err := ep.ClientOpts()
@@ -154,6 +192,49 @@ func getDockerEndpoint(dockerCli command.Cli, config map[string]string) (docker.
 	return ep, nil
 }
 
+// getSSHOptions returns the ssh options of a docker endpoint config, or nil
+// if it has none. Paths are made absolute, so that the context can be used
+// from any directory.
+func getSSHOptions(config map[string]string) (*ssh.Options, error) {
+	var (
+		opts ssh.Options
+		set  bool
+	)
+	for _, f := range []struct {
+		key  string
+		dest *string
+		path bool
+	}{
+		{key: keySSHIdentityFile, dest: &opts.IdentityFile, path: true},
+		{key: keySSHConfigFile, dest: &opts.ConfigFile, path: true},
+		{key: keySSHKnownHostsFile, dest: &opts.KnownHostsFile, path: true},
+		{key: keySSHJumpHost, dest: &opts.JumpHost},
+	} {
+		value, ok := config[f.key]
+		if !ok || value == "" {
+			continue
+		}
+		if f.path {
+			var err error
+			if value, err = filepath.Abs(value); err != nil {
+				return nil, errors.Wrap(err, f.key)
+			}
+			if _, err := os.Stat(value); err != nil {
+				return nil, errors.Wrap(err, f.key)
+			}
+		}
+		*f.dest = value
+		set = true
+	}
+	if !set {
+		return nil, nil
+	}
+	if u, err := url.Parse(config[keyHost]); err != nil || u.Scheme != "ssh" {
+		return nil, errors.New("ssh options can only be set for a docker endpoint with an ssh:// host")
+	}
+	return &opts, nil
+}
+
 func getDockerEndpointMetadataAndTLS(dockerCli command.Cli, config map[string]string) (docker.EndpointMeta, *store.EndpointTLSData, error) {
 	ep, err := getDockerEndpoint(dockerCli, config)
 	if err != nil {
@@ -219,3 +300,20 @@ func getKubernetesEndpointMetadataAndTLS(dockerCli command.Cli, config map[strin
 	}
 	return &ep.EndpointMeta, ep.TLSData.ToStoreTLSData(), nil
 }
+
+// loadConfigOverrides reads the configuration overrides of a context from a
+// JSON file with the same keys as the configuration file
+func loadConfigOverrides(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides map[string]interface{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse configuration overrides file %s", path)
+	}
+	if err := configfile.ValidateOverrides(overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
@@ -0,0 +1,155 @@
+package context
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/kubernetes"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CommandAnnotationPluginEndpoints is added to the commands accepting a flag
+// for each context endpoint type declared by CLI plugins. The flags must be
+// added with AddPluginEndpointFlags before the flags of the command are parsed.
+const CommandAnnotationPluginEndpoints = "com.docker.cli.plugin-endpoints"
+
+// pluginEndpointTypes returns the context endpoint types declared by the valid
+// plugins, sorted by name. When several plugins declare the same type, the
+// first plugin by name wins.
+func pluginEndpointTypes(plugins []manager.Plugin) []manager.EndpointType {
+	plugins = append([]manager.Plugin(nil), plugins...)
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	var (
+		types []manager.EndpointType
+		seen  = map[string]bool{}
+	)
+	for _, p := range plugins {
+		if p.Err != nil {
+			continue
+		}
+		for _, t := range p.EndpointTypes {
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// AddPluginEndpointFlags adds a --<type> flag to cmd for each context endpoint
+// type declared by plugins, if cmd is annotated with
+// CommandAnnotationPluginEndpoints. The flags take comma separated key=value
+// pairs, like the --docker and --kubernetes flags.
+func AddPluginEndpointFlags(cmd *cobra.Command, plugins []manager.Plugin) {
+	if cmd.Annotations[CommandAnnotationPluginEndpoints] == "" {
+		return
+	}
+	flags := cmd.Flags()
+	for _, t := range pluginEndpointTypes(plugins) {
+		if flags.Lookup(t.Name) != nil {
+			continue
+		}
+		flags.Var(&pluginEndpointValue{endpointType: t}, t.Name, pluginEndpointUsage(t))
+	}
+}
+
+// pluginEndpointsFromFlags returns the endpoints set with the flags added by
+// AddPluginEndpointFlags, by type
+func pluginEndpointsFromFlags(flags *pflag.FlagSet) map[string]map[string]string {
+	endpoints := map[string]map[string]string{}
+	flags.Visit(func(f *pflag.Flag) {
+		if v, ok := f.Value.(*pluginEndpointValue); ok {
+			endpoints[v.endpointType.Name] = v.values
+		}
+	})
+	return endpoints
+}
+
+func pluginEndpointUsage(t manager.EndpointType) string {
+	usage := t.Description
+	if usage == "" {
+		usage = "set the " + t.Name + " endpoint"
+	}
+	keys := make([]string, 0, len(t.Keys))
+	for _, k := range t.Keys {
+		keys = append(keys, k.Name)
+	}
+	return usage + " (" + strings.Join(keys, ", ") + ")"
+}
+
+// pluginEndpointValue is the value of the flag setting an endpoint of a type
+// declared by a plugin, given as comma separated key=value pairs
+type pluginEndpointValue struct {
+	endpointType manager.EndpointType
+	values       map[string]string
+}
+
+func (v *pluginEndpointValue) Set(s string) error {
+	fields, err := csv.NewReader(strings.NewReader(s)).Read()
+	if err != nil {
+		return err
+	}
+	if v.values == nil {
+		v.values = map[string]string{}
+	}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return errors.Errorf("%q must be formatted as key=value", field)
+		}
+		if !v.hasKey(kv[0]) {
+			return errors.Errorf("%s endpoint config key %q is not allowed", v.endpointType.Name, kv[0])
+		}
+		v.values[kv[0]] = kv[1]
+	}
+	return nil
+}
+
+func (v *pluginEndpointValue) hasKey(name string) bool {
+	for _, k := range v.endpointType.Keys {
+		if k.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *pluginEndpointValue) Type() string {
+	return "stringToString"
+}
+
+func (v *pluginEndpointValue) String() string {
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := make([]string, 0, len(keys))
+	for _, k := range keys {
+		records = append(records, k+"="+v.values[k])
+	}
+	w.Write(records)
+	w.Flush()
+	return "[" + strings.TrimSpace(buf.String()) + "]"
+}
+
+// validatePluginEndpoints checks that the endpoints set by plugins flags do not
+// replace builtin endpoints
+func validatePluginEndpoints(endpoints map[string]map[string]string) error {
+	for name := range endpoints {
+		if name == docker.DockerEndpoint || name == kubernetes.KubernetesEndpoint {
+			return errors.Errorf("%s endpoint cannot be set as an endpoint declared by a plugin", name)
+		}
+	}
+	return nil
+}
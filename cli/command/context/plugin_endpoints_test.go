@@ -0,0 +1,140 @@
+// +build !windows
+
+package context
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+const testPluginMetadata = `{
+	"SchemaVersion": "0.1.0",
+	"Vendor": "e2e-testing",
+	"EndpointTypes": [{"Name": "my-cloud", "Keys": [{"Name": "url"}, {"Name": "token"}]}]
+}`
+
+func makeFakeCliWithPlugin(t *testing.T) (*test.FakeCli, func()) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-cloud", "#!/bin/sh\ncat <<'EOF'\n"+testPluginMetadata+"\nEOF\n", fs.WithMode(0755)),
+	)
+	cli, cleanup := makeFakeCli(t, withCliConfig(&configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{dir.Path()},
+	}))
+	return cli, func() {
+		cleanup()
+		dir.Remove()
+	}
+}
+
+// withTestPluginEndpointFlags adds the flags of the endpoint types declared by
+// the plugins of cli to cmd, as the docker command does before running it
+func withTestPluginEndpointFlags(t *testing.T, cli command.Cli, cmd *cobra.Command) *cobra.Command {
+	t.Helper()
+	plugins, err := manager.ListPlugins(cli, nil)
+	assert.NilError(t, err)
+	AddPluginEndpointFlags(cmd, plugins)
+	return cmd
+}
+
+func TestPluginEndpointTypes(t *testing.T) {
+	cli, cleanup := makeFakeCliWithPlugin(t)
+	defer cleanup()
+	plugins, err := manager.ListPlugins(cli, nil)
+	assert.NilError(t, err)
+	types := pluginEndpointTypes(plugins)
+	assert.Assert(t, is.Len(types, 1))
+	assert.Equal(t, types[0].Name, "my-cloud")
+	assert.Equal(t, pluginEndpointUsage(types[0]), "set the my-cloud endpoint (url, token)")
+
+	// only the commands creating or updating contexts get the flags
+	cmd := withTestPluginEndpointFlags(t, cli, newCreateCommand(cli))
+	assert.Check(t, cmd.Flags().Lookup("my-cloud") != nil)
+	cmd = withTestPluginEndpointFlags(t, cli, newListCommand(cli))
+	assert.Check(t, is.Nil(cmd.Flags().Lookup("my-cloud")))
+}
+
+func TestCreateUpdatePluginEndpoint(t *testing.T) {
+	cli, cleanup := makeFakeCliWithPlugin(t)
+	defer cleanup()
+
+	cmd := withTestPluginEndpointFlags(t, cli, newCreateCommand(cli))
+	cmd.SetArgs([]string{"--docker", "host=unix:///var/run/docker.sock", "--my-cloud", "url=https://cloud.example.com,token=secret", "test"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	c, err := cli.ContextStore().GetContextMetadata("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(c.Endpoints["my-cloud"], map[string]interface{}{
+		"url":   "https://cloud.example.com",
+		"token": "secret",
+	}))
+	_, ok := c.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta)
+	assert.Check(t, ok)
+
+	cmd = withTestPluginEndpointFlags(t, cli, newUpdateCommand(cli))
+	cmd.SetArgs([]string{"test", "--my-cloud", "url=https://other.example.com"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	c, err = cli.ContextStore().GetContextMetadata("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(c.Endpoints["my-cloud"], map[string]interface{}{
+		"url": "https://other.example.com",
+	}))
+}
+
+func TestCreatePluginEndpointInvalids(t *testing.T) {
+	cli, cleanup := makeFakeCliWithPlugin(t)
+	defer cleanup()
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"--my-cloud", "region=eu", "test"},
+			expectedErr: `my-cloud endpoint config key "region" is not allowed`,
+		},
+		{
+			args:        []string{"--my-cloud", "url", "test"},
+			expectedErr: `"url" must be formatted as key=value`,
+		},
+		{
+			args:        []string{"--other-cloud", "url=https://cloud.example.com", "test"},
+			expectedErr: "unknown flag: --other-cloud",
+		},
+		{
+			args:        []string{"--docker", "host=unix:///var/run/docker.sock"},
+			expectedErr: `"create" requires exactly 1 argument`,
+		},
+	} {
+		cmd := withTestPluginEndpointFlags(t, cli, newCreateCommand(cli))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedErr)
+	}
+}
+
+func TestCreateBuiltinAsPluginEndpoint(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	err := RunCreate(cli, &CreateOptions{
+		Name: "test",
+		Docker: map[string]string{
+			keyHost: "unix:///var/run/docker.sock",
+		},
+		PluginEndpoints: map[string]map[string]string{
+			docker.DockerEndpoint: {"url": "https://cloud.example.com"},
+		},
+	})
+	assert.ErrorContains(t, err, "docker endpoint cannot be set as an endpoint declared by a plugin")
+}
@@ -21,6 +21,12 @@ type UpdateOptions struct {
 	DefaultStackOrchestrator string
 	Docker                   map[string]string
 	Kubernetes               map[string]string
+	// ConfigOverrides is the path of a JSON file overriding the
+	// configuration file when the context is used
+	ConfigOverrides string
+	// PluginEndpoints are the endpoints of the types declared by CLI
+	// plugins, as key=value pairs by type
+	PluginEndpoints map[string]map[string]string
 }
 
 func longUpdateDescription() string {
@@ -51,9 +57,11 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Name = args[0]
+			opts.PluginEndpoints = pluginEndpointsFromFlags(cmd.Flags())
 			return RunUpdate(dockerCli, opts)
 		},
-		Long: longUpdateDescription(),
+		Annotations: map[string]string{CommandAnnotationPluginEndpoints: "true"},
+		Long:        longUpdateDescription(),
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Description, "description", "", "Description of the context")
@@ -63,6 +71,7 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 		"Default orchestrator for stack operations to use with this context (swarm|kubernetes|all)")
 	flags.StringToStringVar(&opts.Docker, "docker", nil, "set the docker endpoint")
 	flags.StringToStringVar(&opts.Kubernetes, "kubernetes", nil, "set the kubernetes endpoint")
+	flags.StringVar(&opts.ConfigOverrides, "config-overrides", "", "JSON file overriding the configuration file when the context is used")
 	return cmd
 }
 
@@ -90,6 +99,11 @@ func RunUpdate(cli command.Cli, o *UpdateOptions) error {
 	if o.Description != "" {
 		dockerContext.Description = o.Description
 	}
+	if o.ConfigOverrides != "" {
+		if dockerContext.Config, err = loadConfigOverrides(o.ConfigOverrides); err != nil {
+			return err
+		}
+	}
 
 	c.Metadata = dockerContext
 
@@ -115,6 +129,12 @@ func RunUpdate(cli command.Cli, o *UpdateOptions) error {
 			tlsDataToReset[kubernetes.KubernetesEndpoint] = kubernetesTLS
 		}
 	}
+	if err := validatePluginEndpoints(o.PluginEndpoints); err != nil {
+		return err
+	}
+	for endpointType, ep := range o.PluginEndpoints {
+		c.Endpoints[endpointType] = ep
+	}
 	if err := validateEndpointsAndOrchestrator(c); err != nil {
 		return err
 	}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/pkg/term"
+	"github.com/pkg/errors"
+)
+
+const (
+	// contextTLSEncryptionPassphrase encrypts the TLS material of contexts
+	// with a passphrase
+	contextTLSEncryptionPassphrase = "passphrase"
+	// contextTLSEncryptionCredentials encrypts the TLS material of contexts
+	// with a random key kept by a credentials helper
+	contextTLSEncryptionCredentials = "credentials"
+
+	// contextPassphraseEnvVar is the environment variable the passphrase
+	// encrypting the TLS material of contexts is read from
+	contextPassphraseEnvVar = "DOCKER_CONTEXT_PASSPHRASE"
+
+	contextTLSKeySize = 32
+)
+
+// contextStoreOptions returns the options of the context store configured in
+// configFile. Passphrases are read from in if it is a terminal, and it is not
+// nil.
+func contextStoreOptions(configFile *configfile.ConfigFile, in *streams.In, out io.Writer) ([]store.Option, error) {
+	if configFile == nil {
+		return nil, nil
+	}
+	switch configFile.ContextTLSEncryption {
+	case "":
+		return nil, nil
+	case contextTLSEncryptionPassphrase:
+		return []store.Option{store.WithTLSEncryption(passphraseKeyGetter(in, out))}, nil
+	case contextTLSEncryptionCredentials:
+		if configFile.CredentialsStore == "" && configFile.CredentialHelpers[configfile.ContextTLSKeyServerAddress] == "" {
+			return nil, errors.Errorf("contextTLSEncryption is set to %q, but no credentials store is configured", contextTLSEncryptionCredentials)
+		}
+		return []store.Option{store.WithTLSEncryption(credentialsKeyGetter(configFile))}, nil
+	default:
+		return nil, errors.Errorf("invalid contextTLSEncryption %q: must be %q or %q", configFile.ContextTLSEncryption, contextTLSEncryptionPassphrase, contextTLSEncryptionCredentials)
+	}
+}
+
+func passphraseKeyGetter(in *streams.In, out io.Writer) store.KeyGetter {
+	return func() ([]byte, error) {
+		if passphrase := os.Getenv(contextPassphraseEnvVar); passphrase != "" {
+			return []byte(passphrase), nil
+		}
+		if in == nil || !in.IsTerminal() {
+			return nil, errors.Errorf("the TLS material of contexts is encrypted with a passphrase: set %s to provide it", contextPassphraseEnvVar)
+		}
+		oldState, err := term.SaveState(in.FD())
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprint(out, "Passphrase of the TLS material of contexts: ")
+		term.DisableEcho(in.FD(), oldState)
+		line, err := bufio.NewReader(in).ReadString('\n')
+		fmt.Fprint(out, "\n")
+		term.RestoreTerminal(in.FD(), oldState)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		passphrase := strings.TrimRight(line, "\r\n")
+		if passphrase == "" {
+			return nil, errors.New("passphrase required")
+		}
+		return []byte(passphrase), nil
+	}
+}
+
+// credentialsKeyGetter returns the key stored in the credentials store of
+// configFile, generating and storing a new one if there is none yet.
+func credentialsKeyGetter(configFile *configfile.ConfigFile) store.KeyGetter {
+	return func() ([]byte, error) {
+		credsStore := configFile.GetCredentialsStore(configfile.ContextTLSKeyServerAddress)
+		auth, err := credsStore.Get(configfile.ContextTLSKeyServerAddress)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the key of the TLS material of contexts from the credentials store")
+		}
+		if auth.Password != "" {
+			return base64.StdEncoding.DecodeString(auth.Password)
+		}
+		key := make([]byte, contextTLSKeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		if err := credsStore.Store(types.AuthConfig{
+			ServerAddress: configfile.ContextTLSKeyServerAddress,
+			Username:      "docker-context",
+			Password:      base64.StdEncoding.EncodeToString(key),
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to store the key of the TLS material of contexts in the credentials store")
+		}
+		return key, nil
+	}
+}
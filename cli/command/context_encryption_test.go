@@ -0,0 +1,67 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+)
+
+func TestContextStoreOptions(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		configFile    *configfile.ConfigFile
+		expectedOpts  int
+		expectedError string
+	}{
+		{
+			doc:        "no encryption",
+			configFile: &configfile.ConfigFile{},
+		},
+		{
+			doc:          "passphrase",
+			configFile:   &configfile.ConfigFile{ContextTLSEncryption: "passphrase"},
+			expectedOpts: 1,
+		},
+		{
+			doc: "credentials",
+			configFile: &configfile.ConfigFile{
+				ContextTLSEncryption: "credentials",
+				CredentialsStore:     "pass",
+			},
+			expectedOpts: 1,
+		},
+		{
+			doc:           "credentials without credentials store",
+			configFile:    &configfile.ConfigFile{ContextTLSEncryption: "credentials"},
+			expectedError: `contextTLSEncryption is set to "credentials", but no credentials store is configured`,
+		},
+		{
+			doc:           "invalid",
+			configFile:    &configfile.ConfigFile{ContextTLSEncryption: "rot13"},
+			expectedError: `invalid contextTLSEncryption "rot13": must be "passphrase" or "credentials"`,
+		},
+	}
+	for _, tc := range testCases {
+		opts, err := contextStoreOptions(tc.configFile, nil, nil)
+		if tc.expectedError != "" {
+			assert.Error(t, err, tc.expectedError, tc.doc)
+			continue
+		}
+		assert.NilError(t, err, tc.doc)
+		assert.Check(t, is.Len(opts, tc.expectedOpts), tc.doc)
+	}
+}
+
+func TestPassphraseKeyGetter(t *testing.T) {
+	defer env.Patch(t, contextPassphraseEnvVar, "")()
+	_, err := passphraseKeyGetter(nil, nil)()
+	assert.Error(t, err, "the TLS material of contexts is encrypted with a passphrase: set DOCKER_CONTEXT_PASSPHRASE to provide it")
+
+	defer env.Patch(t, contextPassphraseEnvVar, "s3cr3t")()
+	key, err := passphraseKeyGetter(nil, nil)()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("s3cr3t", string(key)))
+}
@@ -350,17 +350,21 @@ func TestContainerContextWriteJSON(t *testing.T) {
 			"Status":       "",
 		},
 	}
-	out := bytes.NewBufferString("")
-	err := ContainerWrite(Context{Format: "{{json .}}", Output: out}, containers)
-	if err != nil {
-		t.Fatal(err)
-	}
-	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
-		msg := fmt.Sprintf("Output: line %d: %s", i, line)
-		var m map[string]interface{}
-		err := json.Unmarshal([]byte(line), &m)
-		assert.NilError(t, err, msg)
-		assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+	for _, format := range []Format{"{{json .}}", JSONFormatKey} {
+		out := bytes.NewBufferString("")
+		err := ContainerWrite(Context{Format: format, Output: out}, containers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Assert(t, is.Len(lines, len(expectedJSONs)), string(format))
+		for i, line := range lines {
+			msg := fmt.Sprintf("Format %s: line %d: %s", format, i, line)
+			var m map[string]interface{}
+			err := json.Unmarshal([]byte(line), &m)
+			assert.NilError(t, err, msg)
+			assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+		}
 	}
 }
 
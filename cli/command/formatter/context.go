@@ -1,26 +1,83 @@
 package formatter
 
+import (
+	"encoding/json"
+	"time"
+)
+
 const (
 	// ClientContextTableFormat is the default client context format
 	ClientContextTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.Description}}\t{{.DockerEndpoint}}\t{{.KubernetesEndpoint}}\t{{.StackOrchestrator}}"
+	// ClientContextCheckTableFormat is the table format used by "docker context ls --check",
+	// showing the status of the endpoints instead of the description of the contexts
+	ClientContextCheckTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.DockerEndpoint}}\t{{.DockerStatus}}\t{{.DockerLatency}}\t{{.DockerVersion}}\t{{.DockerTLS}}\t{{.KubernetesEndpoint}}\t{{.KubernetesStatus}}"
 
 	dockerEndpointHeader     = "DOCKER ENDPOINT"
 	kubernetesEndpointHeader = "KUBERNETES ENDPOINT"
 	stackOrchestrastorHeader = "ORCHESTRATOR"
 	quietContextFormat       = "{{.Name}}"
+
+	dockerStatusHeader      = "DOCKER STATUS"
+	dockerLatencyHeader     = "LATENCY"
+	dockerVersionHeader     = "SERVER VERSION"
+	dockerTLSHeader         = "TLS"
+	dockerErrorHeader       = "DOCKER ERROR"
+	kubernetesStatusHeader  = "KUBERNETES STATUS"
+	kubernetesLatencyHeader = "KUBERNETES LATENCY"
+	kubernetesVersionHeader = "KUBERNETES VERSION"
+	kubernetesTLSHeader     = "KUBERNETES TLS"
+	kubernetesErrorHeader   = "KUBERNETES ERROR"
+)
+
+// Status of a checked endpoint
+const (
+	EndpointStatusOK          = "ok"
+	EndpointStatusTimeout     = "timeout"
+	EndpointStatusUnreachable = "unreachable"
+)
+
+// TLS verification result of a checked endpoint
+const (
+	EndpointTLSNone     = "none"
+	EndpointTLSVerified = "verified"
+	EndpointTLSSkipped  = "skipped"
+	EndpointTLSFailed   = "failed"
 )
 
 // NewClientContextFormat returns a Format for rendering using a Context
 func NewClientContextFormat(source string, quiet bool) Format {
+	return newClientContextFormat(source, quiet, ClientContextTableFormat)
+}
+
+// NewClientContextCheckFormat returns a Format for rendering checked contexts
+// using a Context
+func NewClientContextCheckFormat(source string, quiet bool) Format {
+	return newClientContextFormat(source, quiet, ClientContextCheckTableFormat)
+}
+
+func newClientContextFormat(source string, quiet bool, tableFormat string) Format {
 	if quiet {
 		return Format(quietContextFormat)
 	}
 	if source == TableFormatKey {
-		return Format(ClientContextTableFormat)
+		return Format(tableFormat)
 	}
 	return Format(source)
 }
 
+// EndpointCheck is the result of connecting to the endpoint of a context
+type EndpointCheck struct {
+	// Status is one of EndpointStatusOK, EndpointStatusTimeout or
+	// EndpointStatusUnreachable
+	Status        string
+	Latency       time.Duration
+	ServerVersion string
+	// TLS is the result of the verification of the server certificate, one
+	// of the EndpointTLS* constants
+	TLS   string
+	Error string
+}
+
 // ClientContext is a context for display
 type ClientContext struct {
 	Name               string
@@ -29,6 +86,10 @@ type ClientContext struct {
 	KubernetesEndpoint string
 	StackOrchestrator  string
 	Current            bool
+	// DockerCheck and KubernetesCheck are the results of checking the
+	// endpoints of the context, if they were checked
+	DockerCheck     *EndpointCheck
+	KubernetesCheck *EndpointCheck
 }
 
 // ClientContextWrite writes formatted contexts using the Context
@@ -57,12 +118,36 @@ func newClientContextContext() *clientContextContext {
 		"DockerEndpoint":     dockerEndpointHeader,
 		"KubernetesEndpoint": kubernetesEndpointHeader,
 		"StackOrchestrator":  stackOrchestrastorHeader,
+		"DockerStatus":       dockerStatusHeader,
+		"DockerLatency":      dockerLatencyHeader,
+		"DockerVersion":      dockerVersionHeader,
+		"DockerTLS":          dockerTLSHeader,
+		"DockerError":        dockerErrorHeader,
+		"KubernetesStatus":   kubernetesStatusHeader,
+		"KubernetesLatency":  kubernetesLatencyHeader,
+		"KubernetesVersion":  kubernetesVersionHeader,
+		"KubernetesTLS":      kubernetesTLSHeader,
+		"KubernetesError":    kubernetesErrorHeader,
 	}
 	return &ctx
 }
 
+// checkFields are the fields of the results of endpoint checks
+var checkFields = []string{"Status", "Latency", "Version", "TLS", "Error"}
+
 func (c *clientContextContext) MarshalJSON() ([]byte, error) {
-	return MarshalJSON(c)
+	m, err := marshalMap(c)
+	if err != nil {
+		return nil, err
+	}
+	// only output the results of checks if the endpoints were checked
+	if c.c.DockerCheck == nil && c.c.KubernetesCheck == nil {
+		for _, field := range checkFields {
+			delete(m, "Docker"+field)
+			delete(m, "Kubernetes"+field)
+		}
+	}
+	return json.Marshal(m)
 }
 
 func (c *clientContextContext) Current() bool {
@@ -88,3 +173,75 @@ func (c *clientContextContext) KubernetesEndpoint() string {
 func (c *clientContextContext) StackOrchestrator() string {
 	return c.c.StackOrchestrator
 }
+
+func (c *clientContextContext) DockerStatus() string {
+	return endpointStatus(c.c.DockerCheck)
+}
+
+func (c *clientContextContext) DockerLatency() string {
+	return endpointLatency(c.c.DockerCheck)
+}
+
+func (c *clientContextContext) DockerVersion() string {
+	if c.c.DockerCheck == nil {
+		return ""
+	}
+	return c.c.DockerCheck.ServerVersion
+}
+
+func (c *clientContextContext) DockerTLS() string {
+	if c.c.DockerCheck == nil {
+		return ""
+	}
+	return c.c.DockerCheck.TLS
+}
+
+func (c *clientContextContext) DockerError() string {
+	if c.c.DockerCheck == nil {
+		return ""
+	}
+	return c.c.DockerCheck.Error
+}
+
+func (c *clientContextContext) KubernetesStatus() string {
+	return endpointStatus(c.c.KubernetesCheck)
+}
+
+func (c *clientContextContext) KubernetesLatency() string {
+	return endpointLatency(c.c.KubernetesCheck)
+}
+
+func (c *clientContextContext) KubernetesVersion() string {
+	if c.c.KubernetesCheck == nil {
+		return ""
+	}
+	return c.c.KubernetesCheck.ServerVersion
+}
+
+func (c *clientContextContext) KubernetesTLS() string {
+	if c.c.KubernetesCheck == nil {
+		return ""
+	}
+	return c.c.KubernetesCheck.TLS
+}
+
+func (c *clientContextContext) KubernetesError() string {
+	if c.c.KubernetesCheck == nil {
+		return ""
+	}
+	return c.c.KubernetesCheck.Error
+}
+
+func endpointStatus(check *EndpointCheck) string {
+	if check == nil {
+		return ""
+	}
+	return check.Status
+}
+
+func endpointLatency(check *EndpointCheck) string {
+	if check == nil || check.Status != EndpointStatusOK {
+		return ""
+	}
+	return check.Latency.Round(time.Millisecond).String()
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"text/template"
@@ -84,6 +85,9 @@ func (ctx *DiskUsageContext) Write() (err error) {
 	if ctx.Verbose {
 		return ctx.verboseWrite()
 	}
+	if ctx.Format.IsJSON() {
+		return ctx.jsonWrite()
+	}
 	ctx.buffer = bytes.NewBufferString("")
 	ctx.preFormat()
 
@@ -134,6 +138,22 @@ func (ctx *DiskUsageContext) Write() (err error) {
 	return err
 }
 
+func (ctx *DiskUsageContext) jsonWrite() error {
+	ctx.buffer = bytes.NewBufferString("")
+	for _, subContext := range []SubContext{
+		&diskUsageImagesContext{totalSize: ctx.LayersSize, images: ctx.Images},
+		&diskUsageContainersContext{containers: ctx.Containers},
+		&diskUsageVolumesContext{volumes: ctx.Volumes},
+		&diskUsageBuilderContext{builderSize: ctx.BuilderSize, buildCache: ctx.BuildCache},
+	} {
+		if err := ctx.jsonFormat(subContext); err != nil {
+			return err
+		}
+	}
+	_, err := ctx.buffer.WriteTo(ctx.Output)
+	return err
+}
+
 type diskUsageContext struct {
 	Images     []*imageContext
 	Containers []*containerContext
@@ -195,6 +215,9 @@ func (ctx *DiskUsageContext) verboseWrite() error {
 	if ctx.Format == TableFormatKey {
 		return ctx.verboseWriteTable(duc)
 	}
+	if ctx.Format.IsJSON() {
+		return json.NewEncoder(ctx.Output).Encode(duc)
+	}
 
 	ctx.preFormat()
 	tmpl, err := ctx.parseFormat()
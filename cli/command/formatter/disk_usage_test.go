@@ -105,6 +105,20 @@ Build Cache         0                   0                   0B
 			},
 			string(golden.Get(t, "disk-usage-raw-format.golden")),
 		},
+		// JSON Format
+		{
+			DiskUsageContext{
+				Context: Context{
+					Format: NewDiskUsageFormat("json", false),
+				},
+			},
+			string(golden.Get(t, "disk-usage-json-format.golden")),
+		},
+		{
+			DiskUsageContext{Verbose: true, Context: Context{Format: NewDiskUsageFormat("json", true)}},
+			`{"Images":[],"Containers":[],"Volumes":[],"BuildCache":[]}
+`,
+		},
 	}
 
 	for _, testcase := range cases {
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"strings"
 	"text/tabwriter"
@@ -16,6 +17,7 @@ const (
 	TableFormatKey  = "table"
 	RawFormatKey    = "raw"
 	PrettyFormatKey = "pretty"
+	JSONFormatKey   = "json"
 
 	DefaultQuietFormat = "{{.ID}}"
 )
@@ -28,6 +30,12 @@ func (f Format) IsTable() bool {
 	return strings.HasPrefix(string(f), TableFormatKey)
 }
 
+// IsJSON returns true if the format is the json format, which prints every
+// element as a JSON object on a single line
+func (f Format) IsJSON() bool {
+	return string(f) == JSONFormatKey
+}
+
 // Contains returns true if the format contains the substring
 func (f Format) Contains(sub string) bool {
 	return strings.Contains(string(f), sub)
@@ -41,6 +49,8 @@ type Context struct {
 	Format Format
 	// Trunc when set to true will truncate the output of certain fields such as Container ID.
 	Trunc bool
+	// Sort is a comma separated list of FIELD[:asc|:desc] keys to sort the output on.
+	Sort string
 
 	// internal element
 	finalFormat string
@@ -97,9 +107,40 @@ func (c *Context) contextFormat(tmpl *template.Template, subContext SubContext)
 // SubFormat is a function type accepted by Write()
 type SubFormat func(func(SubContext) error) error
 
+func (c *Context) jsonFormat(subContext SubContext) error {
+	var (
+		b   []byte
+		err error
+	)
+	if _, ok := subContext.(json.Marshaler); ok {
+		b, err = json.Marshal(subContext)
+	} else {
+		b, err = MarshalJSON(subContext)
+	}
+	if err != nil {
+		return err
+	}
+	c.buffer.Write(b)
+	c.buffer.WriteString("\n")
+	return nil
+}
+
 // Write the template to the buffer using this Context
 func (c *Context) Write(sub SubContext, f SubFormat) error {
 	c.buffer = bytes.NewBufferString("")
+	if c.Sort != "" {
+		var err error
+		if f, err = sortSubFormat(sub, f, c.Sort); err != nil {
+			return err
+		}
+	}
+	if c.Format.IsJSON() {
+		if err := f(c.jsonFormat); err != nil {
+			return err
+		}
+		_, err := c.buffer.WriteTo(c.Output)
+		return err
+	}
 	c.preFormat()
 
 	tmpl, err := c.parseFormat()
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -301,6 +302,32 @@ image_id: imageID3
 	}
 }
 
+func TestImageContextWriteJSON(t *testing.T) {
+	images := []types.ImageSummary{
+		{ID: "imageID1", RepoTags: []string{"image:tag1"}, Size: 1000},
+		{ID: "imageID2", RepoTags: []string{"<none>:<none>"}, RepoDigests: []string{"image@sha256:cbbf2f9a99b47fc460d422812b6a5adff7dfee951d8fa2e4a98caa0382cfbdbf"}},
+	}
+	expected := []map[string]interface{}{
+		{"ID": "imageID1", "Repository": "image", "Tag": "tag1", "Size": "1kB"},
+		{"ID": "imageID2", "Repository": "image", "Tag": "<none>", "Size": "0B"},
+	}
+
+	out := bytes.NewBufferString("")
+	err := ImageWrite(ImageContext{Context: Context{Format: NewImageFormat(JSONFormatKey, false, false), Output: out}}, images)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Assert(t, is.Len(lines, len(expected)))
+	for i, line := range lines {
+		msg := fmt.Sprintf("Output: line %d: %s", i, line)
+		var m map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &m), msg)
+		for key, value := range expected[i] {
+			assert.Check(t, is.Equal(value, m[key]), msg)
+		}
+		assert.Check(t, is.Contains(m, "CreatedSince"), msg)
+	}
+}
+
 func TestImageContextWriteWithNoImage(t *testing.T) {
 	out := bytes.NewBufferString("")
 	images := []types.ImageSummary{}
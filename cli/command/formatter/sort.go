@@ -0,0 +1,278 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"vbom.ml/util/sortorder"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+// AddSortFlag adds the --sort flag, sorting the output of a list command on
+// the fields of its SubContext.
+func AddSortFlag(flags *pflag.FlagSet, p *string) {
+	flags.StringVar(p, "sort", "", "Sort the output on the given fields (FIELD[:asc|:desc][,...])")
+}
+
+type sortKey struct {
+	field      string
+	descending bool
+}
+
+// parseSort parses a comma separated list of FIELD[:asc|:desc] sort keys,
+// checking that the fields are fields of sub. Field names are not case
+// sensitive.
+func parseSort(sub SubContext, spec string) ([]sortKey, error) {
+	fields := sortableFields(sub)
+	var keys []sortKey
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, order := item, sortAscending
+		if i := strings.LastIndex(item, ":"); i >= 0 {
+			name, order = item[:i], strings.ToLower(item[i+1:])
+		}
+		if order != sortAscending && order != sortDescending {
+			return nil, errors.Errorf("invalid sort order %q for field %s: must be %q or %q", order, name, sortAscending, sortDescending)
+		}
+		field, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, errors.Errorf("invalid sort field %q: available fields are %s", name, strings.Join(sortedFieldNames(fields), ", "))
+		}
+		keys = append(keys, sortKey{field: field, descending: order == sortDescending})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no field to sort on")
+	}
+	return keys, nil
+}
+
+// sortableFields returns the fields of sub, that is the exported methods that
+// can be used in a template without arguments, keyed by their lower case name.
+func sortableFields(sub SubContext) map[string]string {
+	fields := map[string]string{}
+	typ := reflect.TypeOf(sub)
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if _, blackListed := unmarshallableNames[method.Name]; blackListed || !unicode.IsUpper(rune(method.Name[0])) {
+			continue
+		}
+		// the receiver is the first argument
+		if method.Type.NumIn() == 1 && method.Type.NumOut() == 1 {
+			fields[strings.ToLower(method.Name)] = method.Name
+		}
+	}
+	return fields
+}
+
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for _, name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortSubFormat returns a SubFormat rendering the SubContexts of f in the
+// order given by spec.
+func sortSubFormat(sub SubContext, f SubFormat, spec string) (SubFormat, error) {
+	keys, err := parseSort(sub, spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(format func(SubContext) error) error {
+		var subContexts []SubContext
+		if err := f(func(subContext SubContext) error {
+			subContexts = append(subContexts, subContext)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		columns := make([][]sortValue, len(keys))
+		for i, key := range keys {
+			columns[i] = sortValues(subContexts, key.field)
+		}
+		indexes := make([]int, len(subContexts))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			for i, key := range keys {
+				x, y := columns[i][indexes[a]], columns[i][indexes[b]]
+				if x.equal(y) {
+					continue
+				}
+				if key.descending {
+					return y.less(x)
+				}
+				return x.less(y)
+			}
+			return false
+		})
+
+		for _, i := range indexes {
+			if err := format(subContexts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// sortValue is the value of a field of a SubContext. If every value of the
+// field can be parsed as a number, a size or a time, they are compared on
+// number, otherwise on their natural order.
+type sortValue struct {
+	text   string
+	number float64
+	parsed bool
+}
+
+func (v sortValue) less(other sortValue) bool {
+	if v.parsed && other.parsed {
+		return v.number < other.number
+	}
+	return sortorder.NaturalLess(v.text, other.text)
+}
+
+func (v sortValue) equal(other sortValue) bool {
+	if v.parsed && other.parsed {
+		return v.number == other.number
+	}
+	return v.text == other.text
+}
+
+// sortValues returns the values of field for every SubContext
+func sortValues(subContexts []SubContext, field string) []sortValue {
+	values := make([]sortValue, len(subContexts))
+	for i, subContext := range subContexts {
+		if method := reflect.ValueOf(subContext).MethodByName(field); method.IsValid() {
+			values[i].text = fmt.Sprint(method.Call(nil)[0].Interface())
+		}
+	}
+
+	now := time.Now()
+	for _, parse := range []func(string) (float64, bool){
+		parseNumber,
+		parseSize,
+		func(s string) (float64, bool) { return parseTime(s, now) },
+	} {
+		if parseAll(values, parse) {
+			break
+		}
+	}
+	return values
+}
+
+// parseAll parses every non-empty value with parse. Values are only marked as
+// parsed if all of them could be parsed. Empty values sort first.
+func parseAll(values []sortValue, parse func(string) (float64, bool)) bool {
+	numbers := make([]float64, len(values))
+	empty := true
+	for i, value := range values {
+		text := strings.TrimSpace(value.text)
+		if text == "" {
+			numbers[i] = -1 << 63
+			continue
+		}
+		number, ok := parse(text)
+		if !ok {
+			return false
+		}
+		numbers[i] = number
+		empty = false
+	}
+	if empty {
+		return false
+	}
+	for i := range values {
+		values[i].number = numbers[i]
+		values[i].parsed = true
+	}
+	return true
+}
+
+// parseNumber parses finite numbers only, so that values such as "inf" or
+// "NaN" are compared as text.
+func parseNumber(s string) (float64, bool) {
+	number, err := strconv.ParseFloat(s, 64)
+	return number, err == nil && !math.IsInf(number, 0) && !math.IsNaN(number)
+}
+
+// parseSize parses human readable sizes, such as "12.3MB" or "1.5GiB". Only
+// the first word is considered, so that the size of containers ("0B (virtual
+// 1.2MB)") can be parsed too.
+func parseSize(s string) (float64, bool) {
+	s = strings.Fields(s)[0]
+	parse := units.FromHumanSize
+	if strings.Contains(strings.ToLower(s), "ib") {
+		parse = units.RAMInBytes
+	}
+	size, err := parse(s)
+	return float64(size), err == nil
+}
+
+var (
+	timeLayouts = []string{
+		"2006-01-02 15:04:05 -0700 MST",
+		"2006-01-02 15:04:05 -0700 -0700",
+		time.RFC3339Nano,
+	}
+
+	// relativeTimeRegexp matches the output of units.HumanDuration followed
+	// by "ago", e.g. "About an hour ago" or "3 days ago"
+	relativeTimeRegexp = regexp.MustCompile(`^(?:Less than a second|(About an?|\d+) (second|minute|hour|day|week|month|year)s?) ago$`)
+
+	relativeTimeUnits = map[string]time.Duration{
+		"second": time.Second,
+		"minute": time.Minute,
+		"hour":   time.Hour,
+		"day":    24 * time.Hour,
+		"week":   7 * 24 * time.Hour,
+		"month":  30 * 24 * time.Hour,
+		"year":   365 * 24 * time.Hour,
+	}
+)
+
+// parseTime parses absolute times, as well as times relative to now such as
+// "3 minutes ago", which are converted to absolute times so that both sort
+// the same way.
+func parseTime(s string, now time.Time) (float64, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return float64(t.UnixNano()), true
+		}
+	}
+
+	matches := relativeTimeRegexp.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, false
+	}
+	var ago time.Duration
+	if matches[1] != "" {
+		count := 1
+		if matches[1][0] != 'A' {
+			count, _ = strconv.Atoi(matches[1])
+		}
+		ago = time.Duration(count) * relativeTimeUnits[matches[2]]
+	}
+	return float64(now.Add(-ago).UnixNano()), true
+}
@@ -0,0 +1,104 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseSort(t *testing.T) {
+	keys, err := parseSort(&volumeContext{}, "driver, NAME:desc,scope:ASC")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]sortKey{
+		{field: "Driver"},
+		{field: "Name", descending: true},
+		{field: "Scope"},
+	}, keys, cmp.AllowUnexported(sortKey{})))
+
+	testCases := []struct {
+		spec          string
+		expectedError string
+	}{
+		{spec: "unknown", expectedError: `invalid sort field "unknown": available fields are Driver, Labels, Links, Mountpoint, Name, Scope, Size`},
+		{spec: "name:up", expectedError: `invalid sort order "up" for field name: must be "asc" or "desc"`},
+		{spec: " , ", expectedError: "no field to sort on"},
+	}
+	for _, tc := range testCases {
+		_, err := parseSort(&volumeContext{}, tc.spec)
+		assert.Check(t, is.Error(err, tc.expectedError), tc.spec)
+	}
+}
+
+func TestSortWrite(t *testing.T) {
+	volumes := []*types.Volume{
+		{Name: "vol10", Driver: "local", UsageData: &types.VolumeUsageData{Size: 2000}},
+		{Name: "vol2", Driver: "nfs", UsageData: &types.VolumeUsageData{Size: 30000}},
+		{Name: "vol1", Driver: "local", UsageData: &types.VolumeUsageData{Size: 1000000}},
+	}
+	testCases := []struct {
+		sort     string
+		expected string
+	}{
+		{sort: "name", expected: "vol1\nvol2\nvol10\n"},
+		{sort: "name:desc", expected: "vol10\nvol2\nvol1\n"},
+		{sort: "size", expected: "vol10\nvol2\nvol1\n"},
+		{sort: "driver:desc,name", expected: "vol2\nvol1\nvol10\n"},
+	}
+	for _, tc := range testCases {
+		out := bytes.NewBufferString("")
+		err := VolumeWrite(Context{Format: "{{.Name}}", Output: out, Sort: tc.sort}, volumes)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(tc.expected, out.String()), tc.sort)
+	}
+
+	err := VolumeWrite(Context{Format: "{{.Name}}", Output: &bytes.Buffer{}, Sort: "unknown"}, volumes)
+	assert.Check(t, is.ErrorContains(err, "invalid sort field"))
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2019, 2, 14, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2019-02-14 09:00:00 +0000 UTC", expected: now.Add(-time.Hour)},
+		{value: "2019-02-14T08:00:00Z", expected: now.Add(-2 * time.Hour)},
+		{value: "Less than a second ago", expected: now},
+		{value: "About a minute ago", expected: now.Add(-time.Minute)},
+		{value: "About an hour ago", expected: now.Add(-time.Hour)},
+		{value: "3 days ago", expected: now.Add(-72 * time.Hour)},
+	}
+	for _, tc := range testCases {
+		value, ok := parseTime(tc.value, now)
+		assert.Check(t, ok, tc.value)
+		assert.Check(t, is.Equal(float64(tc.expected.UnixNano()), value), tc.value)
+	}
+
+	_, ok := parseTime("Up 3 minutes", now)
+	assert.Check(t, !ok)
+}
+
+func TestSortValuesMixed(t *testing.T) {
+	values := []sortValue{{text: "10"}, {text: "9"}, {text: "N/A"}}
+	assert.Check(t, !parseAll(values, parseNumber))
+	assert.Check(t, values[1].less(values[0]))
+
+	for _, text := range []string{"inf", "-Inf", "NaN", "1e400"} {
+		_, ok := parseNumber(text)
+		assert.Check(t, !ok, text)
+	}
+	names := []sortValue{{text: "nan"}, {text: "inf"}, {text: "infra"}}
+	assert.Check(t, !parseAll(names, parseNumber))
+
+	sizes := []sortValue{{text: "1.5kB"}, {text: "0B (virtual 12MB)"}, {text: "2MiB"}, {text: ""}}
+	assert.Check(t, parseAll(sizes, parseSize))
+	assert.Check(t, is.Equal(float64(1500), sizes[0].number))
+	assert.Check(t, is.Equal(float64(0), sizes[1].number))
+	assert.Check(t, is.Equal(float64(2*1024*1024), sizes[2].number))
+	assert.Check(t, sizes[3].less(sizes[1]))
+}
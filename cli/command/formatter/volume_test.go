@@ -149,17 +149,21 @@ func TestVolumeContextWriteJSON(t *testing.T) {
 		{"Driver": "foo", "Labels": "", "Links": "N/A", "Mountpoint": "", "Name": "foobar_baz", "Scope": "", "Size": "N/A"},
 		{"Driver": "bar", "Labels": "", "Links": "N/A", "Mountpoint": "", "Name": "foobar_bar", "Scope": "", "Size": "N/A"},
 	}
-	out := bytes.NewBufferString("")
-	err := VolumeWrite(Context{Format: "{{json .}}", Output: out}, volumes)
-	if err != nil {
-		t.Fatal(err)
-	}
-	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
-		msg := fmt.Sprintf("Output: line %d: %s", i, line)
-		var m map[string]interface{}
-		err := json.Unmarshal([]byte(line), &m)
-		assert.NilError(t, err, msg)
-		assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+	for _, format := range []Format{"{{json .}}", NewVolumeFormat(JSONFormatKey, false)} {
+		out := bytes.NewBufferString("")
+		err := VolumeWrite(Context{Format: format, Output: out}, volumes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Assert(t, is.Len(lines, len(expectedJSONs)), string(format))
+		for i, line := range lines {
+			msg := fmt.Sprintf("Format %s: line %d: %s", format, i, line)
+			var m map[string]interface{}
+			err := json.Unmarshal([]byte(line), &m)
+			assert.NilError(t, err, msg)
+			assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+		}
 	}
 }
 
@@ -170,6 +170,63 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	return cmd
 }
 
+// BuildOptions defines what and how to build, for builds started by other
+// commands than `docker build`
+type BuildOptions struct {
+	Context     string
+	Dockerfile  string
+	Tags        []string
+	BuildArgs   map[string]*string
+	Labels      map[string]string
+	CacheFrom   []string
+	NetworkMode string
+	Target      string
+	NoCache     bool
+	Pull        bool
+	Quiet       bool
+}
+
+// RunBuild builds an image in the same way as `docker build`, using BuildKit
+// if it is enabled
+func RunBuild(dockerCli command.Cli, opts BuildOptions) error {
+	options := newBuildOptions()
+	options.context = opts.Context
+	options.dockerfileName = opts.Dockerfile
+	options.cacheFrom = opts.CacheFrom
+	options.networkMode = opts.NetworkMode
+	if options.networkMode == "" {
+		options.networkMode = "default"
+	}
+	options.target = opts.Target
+	options.noCache = opts.NoCache
+	options.pull = opts.Pull
+	options.quiet = opts.Quiet
+	options.rm = true
+	options.progress = "auto"
+	options.untrusted = !dockerCli.ContentTrustEnabled()
+
+	for _, tag := range opts.Tags {
+		if err := options.tags.Set(tag); err != nil {
+			return err
+		}
+	}
+	for key, value := range opts.BuildArgs {
+		arg := key
+		if value != nil {
+			arg = key + "=" + *value
+		}
+		if err := options.buildArgs.Set(arg); err != nil {
+			return err
+		}
+	}
+	for key, value := range opts.Labels {
+		if err := options.labels.Set(key + "=" + value); err != nil {
+			return err
+		}
+	}
+	return runBuild(dockerCli, options)
+}
+
 // lastProgressOutput is the same as progress.Output except
 // that it only output with the last update. It is used in
 // non terminal scenarios to suppress verbose messages
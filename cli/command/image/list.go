@@ -19,6 +19,7 @@ type imagesOptions struct {
 	noTrunc     bool
 	showDigests bool
 	format      string
+	sort        string
 	filter      opts.FilterOpt
 }
 
@@ -45,6 +46,7 @@ func NewImagesCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.BoolVar(&options.showDigests, "digests", false, "Show digests")
 	flags.StringVar(&options.format, "format", "", "Pretty-print images using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -88,6 +90,7 @@ func runImages(dockerCli command.Cli, options imagesOptions) error {
 		Context: formatter.Context{
 			Output: dockerCli.Out(),
 			Format: formatter.NewImageFormat(format, options.quiet, options.showDigests),
+			Sort:   options.sort,
 			Trunc:  !options.noTrunc,
 		},
 		Digest: options.showDigests,
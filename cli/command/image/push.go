@@ -11,35 +11,36 @@ import (
 	"github.com/spf13/cobra"
 )
 
-type pushOptions struct {
-	remote    string
-	untrusted bool
+// PushOptions defines what and how to push
+type PushOptions struct {
+	Remote    string
+	Untrusted bool
 }
 
 // NewPushCommand creates a new `docker push` command
 func NewPushCommand(dockerCli command.Cli) *cobra.Command {
-	var opts pushOptions
+	var opts PushOptions
 
 	cmd := &cobra.Command{
 		Use:   "push [OPTIONS] NAME[:TAG]",
 		Short: "Push an image or a repository to a registry",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.remote = args[0]
+			opts.Remote = args[0]
 			return RunPush(dockerCli, opts)
 		},
 	}
 
 	flags := cmd.Flags()
 
-	command.AddTrustSigningFlags(flags, &opts.untrusted, dockerCli.ContentTrustEnabled())
+	command.AddTrustSigningFlags(flags, &opts.Untrusted, dockerCli.ContentTrustEnabled())
 
 	return cmd
 }
 
 // RunPush performs a push against the engine based on the specified options
-func RunPush(dockerCli command.Cli, opts pushOptions) error {
-	ref, err := reference.ParseNormalizedNamed(opts.remote)
+func RunPush(dockerCli command.Cli, opts PushOptions) error {
+	ref, err := reference.ParseNormalizedNamed(opts.Remote)
 	if err != nil {
 		return err
 	}
@@ -56,7 +57,7 @@ func RunPush(dockerCli command.Cli, opts pushOptions) error {
 	authConfig := command.ResolveAuthConfig(ctx, dockerCli, repoInfo.Index)
 	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(dockerCli, repoInfo.Index, "push")
 
-	if !opts.untrusted {
+	if !opts.Untrusted {
 		return TrustedPush(ctx, dockerCli, repoInfo, ref, authConfig, requestPrivilege)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"text/template"
 
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/templates"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -36,10 +37,14 @@ func NewTemplateInspector(outputStream io.Writer, tmpl *template.Template) Inspe
 }
 
 // NewTemplateInspectorFromString creates a new TemplateInspector from a string
-// which is compiled into a template.
+// which is compiled into a template. The "json" format is not a template, and
+// prints every element as a JSON object on a single line.
 func NewTemplateInspectorFromString(out io.Writer, tmplStr string) (Inspector, error) {
-	if tmplStr == "" {
+	switch tmplStr {
+	case "":
 		return NewIndentedInspector(out), nil
+	case formatter.JSONFormatKey:
+		return NewJSONInspector(out), nil
 	}
 
 	tmpl, err := templates.Parse(tmplStr)
@@ -197,3 +202,39 @@ func (i *IndentedInspector) Flush() error {
 	_, err := io.WriteString(i.outputStream, "\n")
 	return err
 }
+
+// JSONInspector writes every element as a JSON object on a single line.
+type JSONInspector struct {
+	outputStream io.Writer
+	buffer       *bytes.Buffer
+}
+
+// NewJSONInspector generates a new JSONInspector.
+func NewJSONInspector(outputStream io.Writer) Inspector {
+	return &JSONInspector{
+		outputStream: outputStream,
+		buffer:       new(bytes.Buffer),
+	}
+}
+
+// Inspect writes the raw element, or the typed element if there is no raw
+// element, as compact json.
+func (i *JSONInspector) Inspect(typedElement interface{}, rawElement []byte) error {
+	if rawElement == nil {
+		var err error
+		if rawElement, err = json.Marshal(typedElement); err != nil {
+			return err
+		}
+	}
+	if err := json.Compact(i.buffer, rawElement); err != nil {
+		return errors.Errorf("unable to read inspect data: %v", err)
+	}
+	i.buffer.WriteByte('\n')
+	return nil
+}
+
+// Flush writes the result of inspecting all elements into the output stream.
+func (i *JSONInspector) Flush() error {
+	_, err := io.Copy(i.outputStream, i.buffer)
+	return err
+}
@@ -222,6 +222,21 @@ func TestIndentedInspectorRawElements(t *testing.T) {
 	}
 }
 
+func TestJSONInspector(t *testing.T) {
+	b := new(bytes.Buffer)
+	i, err := NewTemplateInspectorFromString(b, "json")
+	assert.NilError(t, err)
+	assert.NilError(t, i.Inspect(testElement{"0.0.0.0"}, nil))
+	assert.NilError(t, i.Inspect(testElement{"1.1.1.1"}, []byte(`{
+    "Dns": "1.1.1.1",
+    "Node": "1"
+}`)))
+	assert.NilError(t, i.Flush())
+	assert.Check(t, is.Equal(`{"Dns":"0.0.0.0"}
+{"Dns":"1.1.1.1","Node":"1"}
+`, b.String()))
+}
+
 // moby/moby#32235
 // This test verifies that even if `tryRawInspectFallback` is called the fields containing
 // numerical values are displayed correctly.
@@ -181,17 +181,21 @@ func TestNetworkContextWriteJSON(t *testing.T) {
 		{"Driver": "", "ID": "networkID2", "IPv6": "false", "Internal": "false", "Labels": "", "Name": "foobar_bar", "Scope": "", "CreatedAt": "0001-01-01 00:00:00 +0000 UTC"},
 	}
 
-	out := bytes.NewBufferString("")
-	err := FormatWrite(formatter.Context{Format: "{{json .}}", Output: out}, networks)
-	if err != nil {
-		t.Fatal(err)
-	}
-	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
-		msg := fmt.Sprintf("Output: line %d: %s", i, line)
-		var m map[string]interface{}
-		err := json.Unmarshal([]byte(line), &m)
-		assert.NilError(t, err, msg)
-		assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+	for _, format := range []formatter.Format{"{{json .}}", NewFormat(formatter.JSONFormatKey, false)} {
+		out := bytes.NewBufferString("")
+		err := FormatWrite(formatter.Context{Format: format, Output: out}, networks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Assert(t, is.Len(lines, len(expectedJSONs)), string(format))
+		for i, line := range lines {
+			msg := fmt.Sprintf("Format %s: line %d: %s", format, i, line)
+			var m map[string]interface{}
+			err := json.Unmarshal([]byte(line), &m)
+			assert.NilError(t, err, msg)
+			assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+		}
 	}
 }
 
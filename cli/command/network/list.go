@@ -17,6 +17,7 @@ type listOptions struct {
 	quiet   bool
 	noTrunc bool
 	format  string
+	sort    string
 	filter  opts.FilterOpt
 }
 
@@ -37,6 +38,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display network IDs")
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Do not truncate the output")
 	flags.StringVar(&options.format, "format", "", "Pretty-print networks using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Provide filter values (e.g. 'driver=bridge')")
 
 	return cmd
@@ -66,6 +68,7 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	networksCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
 		Trunc:  !options.noTrunc,
 	}
 	return FormatWrite(networksCtx, networkResources)
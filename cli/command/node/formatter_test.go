@@ -248,17 +248,21 @@ func TestNodeContextWriteJSON(t *testing.T) {
 			{ID: "nodeID2", Description: swarm.NodeDescription{Hostname: "foobar_bar", TLSInfo: swarm.TLSInfo{TrustRoot: "no"}}},
 			{ID: "nodeID3", Description: swarm.NodeDescription{Hostname: "foobar_boo", Engine: swarm.EngineDescription{EngineVersion: "18.03.0-ce"}}},
 		}
-		out := bytes.NewBufferString("")
-		err := FormatWrite(formatter.Context{Format: "{{json .}}", Output: out}, nodes, testcase.info)
-		if err != nil {
-			t.Fatal(err)
-		}
-		for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
-			msg := fmt.Sprintf("Output: line %d: %s", i, line)
-			var m map[string]interface{}
-			err := json.Unmarshal([]byte(line), &m)
-			assert.NilError(t, err, msg)
-			assert.Check(t, is.DeepEqual(testcase.expected[i], m), msg)
+		for _, format := range []formatter.Format{"{{json .}}", NewFormat(formatter.JSONFormatKey, false)} {
+			out := bytes.NewBufferString("")
+			err := FormatWrite(formatter.Context{Format: format, Output: out}, nodes, testcase.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			assert.Assert(t, is.Len(lines, len(testcase.expected)), string(format))
+			for i, line := range lines {
+				msg := fmt.Sprintf("Format %s: line %d: %s", format, i, line)
+				var m map[string]interface{}
+				err := json.Unmarshal([]byte(line), &m)
+				assert.NilError(t, err, msg)
+				assert.Check(t, is.DeepEqual(testcase.expected[i], m), msg)
+			}
 		}
 	}
 }
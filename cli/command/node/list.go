@@ -16,6 +16,7 @@ import (
 type listOptions struct {
 	quiet  bool
 	format string
+	sort   string
 	filter opts.FilterOpt
 }
 
@@ -34,6 +35,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print nodes using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -70,6 +72,7 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	nodesCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
 	}
 	sort.Slice(nodes, func(i, j int) bool {
 		return sortorder.NaturalLess(nodes[i].Description.Hostname, nodes[j].Description.Hostname)
@@ -16,6 +16,7 @@ type listOptions struct {
 	quiet   bool
 	noTrunc bool
 	format  string
+	sort    string
 	filter  opts.FilterOpt
 }
 
@@ -37,6 +38,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display plugin IDs")
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.StringVar(&options.format, "format", "", "Pretty-print plugins using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Provide filter values (e.g. 'enabled=true')")
 
 	return cmd
@@ -64,6 +66,7 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	pluginsCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
 		Trunc:  !options.noTrunc,
 	}
 	return FormatWrite(pluginsCtx, plugins)
@@ -16,6 +16,7 @@ import (
 type listOptions struct {
 	quiet  bool
 	format string
+	sort   string
 	filter opts.FilterOpt
 }
 
@@ -35,6 +36,7 @@ func newSecretListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVarP(&options.format, "format", "", "", "Pretty-print secrets using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -64,6 +66,7 @@ func runSecretList(dockerCli command.Cli, options listOptions) error {
 	secretCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
+		Sort:   options.sort,
 	}
 	return FormatWrite(secretCtx, secrets)
 }
@@ -184,17 +184,21 @@ func TestServiceContextWriteJSON(t *testing.T) {
 		{"ID": "id_bar", "Name": "bar", "Mode": "replicated", "Replicas": "2/4", "Image": "", "Ports": "*:80->8080/tcp"},
 	}
 
-	out := bytes.NewBufferString("")
-	err := ListFormatWrite(formatter.Context{Format: "{{json .}}", Output: out}, services, info)
-	if err != nil {
-		t.Fatal(err)
-	}
-	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
-		msg := fmt.Sprintf("Output: line %d: %s", i, line)
-		var m map[string]interface{}
-		err := json.Unmarshal([]byte(line), &m)
-		assert.NilError(t, err, msg)
-		assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+	for _, format := range []formatter.Format{"{{json .}}", NewListFormat(formatter.JSONFormatKey, false)} {
+		out := bytes.NewBufferString("")
+		err := ListFormatWrite(formatter.Context{Format: format, Output: out}, services, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Assert(t, is.Len(lines, len(expectedJSONs)), string(format))
+		for i, line := range lines {
+			msg := fmt.Sprintf("Format %s: line %d: %s", format, i, line)
+			var m map[string]interface{}
+			err := json.Unmarshal([]byte(line), &m)
+			assert.NilError(t, err, msg)
+			assert.Check(t, is.DeepEqual(expectedJSONs[i], m), msg)
+		}
 	}
 }
 func TestServiceContextWriteJSONField(t *testing.T) {
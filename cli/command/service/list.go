@@ -20,6 +20,7 @@ import (
 type listOptions struct {
 	quiet  bool
 	format string
+	sort   string
 	filter opts.FilterOpt
 }
 
@@ -39,6 +40,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print services using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -90,6 +92,7 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	servicesCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewListFormat(format, options.quiet),
+		Sort:   options.sort,
 	}
 	return ListFormatWrite(servicesCtx, services, info)
 }
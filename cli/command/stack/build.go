@@ -0,0 +1,157 @@
+package stack
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/pkg/urlutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newBuildCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Build
+
+	cmd := &cobra.Command{
+		Use:   "build [OPTIONS] [SERVICE...]",
+		Short: "Build the images of the services of a stack",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Services = args
+			config, err := loader.LoadComposefile(dockerCli, options.Deploy{Composefiles: opts.Composefiles, Build: true})
+			if err != nil {
+				return err
+			}
+			return RunBuild(dockerCli, config, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.BoolVar(&opts.Push, "push", false, "Push the images after building them")
+	flags.BoolVar(&opts.NoCache, "no-cache", false, "Do not use cache when building the images")
+	flags.BoolVar(&opts.Pull, "pull", false, "Always attempt to pull a newer version of the base images")
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress the build output")
+	return cmd
+}
+
+// serviceBuild is the build of the image of a single service
+type serviceBuild struct {
+	service string
+	options image.BuildOptions
+}
+
+// RunBuild builds the images of the services of config that have a `build`
+// section, and tags them with the image of the service. The images are
+// pushed afterwards if opts.Push is set.
+func RunBuild(dockerCli command.Cli, config *composetypes.Config, opts options.Build) error {
+	workingDir, err := loader.WorkingDir(opts.Composefiles)
+	if err != nil {
+		return err
+	}
+	builds, err := getServiceBuilds(config, workingDir, opts)
+	if err != nil {
+		return err
+	}
+	if len(builds) == 0 {
+		fmt.Fprintln(dockerCli.Err(), "No service to build")
+		return nil
+	}
+
+	for _, build := range builds {
+		fmt.Fprintf(dockerCli.Out(), "Building service %s\n", build.service)
+		if err := image.RunBuild(dockerCli, build.options); err != nil {
+			return errors.Wrapf(err, "failed to build service %s", build.service)
+		}
+	}
+	if !opts.Push {
+		return nil
+	}
+
+	pushed := map[string]bool{}
+	for _, build := range builds {
+		tag := build.options.Tags[0]
+		if pushed[tag] {
+			continue
+		}
+		pushed[tag] = true
+		fmt.Fprintf(dockerCli.Out(), "Pushing image %s\n", tag)
+		pushOpts := image.PushOptions{Remote: tag, Untrusted: !dockerCli.ContentTrustEnabled()}
+		if err := image.RunPush(dockerCli, pushOpts); err != nil {
+			return errors.Wrapf(err, "failed to push image %s of service %s", tag, build.service)
+		}
+	}
+	return nil
+}
+
+// getServiceBuilds returns the builds of the services of config that have a
+// `build` section, restricted to opts.Services if it is not empty. Local
+// build contexts are resolved relative to workingDir.
+func getServiceBuilds(config *composetypes.Config, workingDir string, opts options.Build) ([]serviceBuild, error) {
+	services := map[string]composetypes.ServiceConfig{}
+	for _, service := range config.Services {
+		services[service.Name] = service
+	}
+	names := opts.Services
+	if len(names) == 0 {
+		for name, service := range services {
+			if service.Build.Context != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	builds := []serviceBuild{}
+	for _, name := range names {
+		service, ok := services[name]
+		switch {
+		case !ok:
+			return nil, errors.Errorf("no such service: %s", name)
+		case service.Build.Context == "":
+			return nil, errors.Errorf("service %s does not have a build section", name)
+		case service.Image == "":
+			return nil, errors.Errorf("service %s has a build section but no image to tag the build with", name)
+		}
+
+		buildContext := service.Build.Context
+		dockerfile := service.Build.Dockerfile
+		if !urlutil.IsURL(buildContext) && !urlutil.IsGitURL(buildContext) {
+			buildContext = absPath(workingDir, buildContext)
+			// like `docker build -f`, a relative Dockerfile would be
+			// resolved against the current directory
+			if dockerfile != "" {
+				dockerfile = absPath(buildContext, dockerfile)
+			}
+		}
+		builds = append(builds, serviceBuild{
+			service: name,
+			options: image.BuildOptions{
+				Context:     buildContext,
+				Dockerfile:  dockerfile,
+				Tags:        []string{service.Image},
+				BuildArgs:   service.Build.Args,
+				Labels:      service.Build.Labels,
+				CacheFrom:   service.Build.CacheFrom,
+				NetworkMode: service.Build.Network,
+				Target:      service.Build.Target,
+				NoCache:     opts.NoCache,
+				Pull:        opts.Pull,
+				Quiet:       opts.Quiet,
+			},
+		})
+	}
+	return builds, nil
+}
+
+func absPath(workingDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(workingDir, path)
+}
@@ -0,0 +1,115 @@
+package stack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/command/stack/options"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestGetServiceBuilds(t *testing.T) {
+	foo := "foo"
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:  "web",
+				Image: "registry.example.com/web:1.0",
+				Build: composetypes.BuildConfig{
+					Context:    "./web",
+					Dockerfile: "Dockerfile.prod",
+					Args:       composetypes.MappingWithEquals{"FOO": &foo},
+					Target:     "prod",
+				},
+			},
+			{
+				Name:  "api",
+				Image: "api",
+				Build: composetypes.BuildConfig{Context: "git://github.com/example/api"},
+			},
+			{Name: "db", Image: "postgres"},
+		},
+	}
+
+	builds, err := getServiceBuilds(config, "/project", options.Build{NoCache: true})
+	assert.NilError(t, err)
+	expected := []serviceBuild{
+		{
+			service: "api",
+			options: image.BuildOptions{
+				Context: "git://github.com/example/api",
+				Tags:    []string{"api"},
+				NoCache: true,
+			},
+		},
+		{
+			service: "web",
+			options: image.BuildOptions{
+				Context:    filepath.FromSlash("/project/web"),
+				Dockerfile: filepath.FromSlash("/project/web/Dockerfile.prod"),
+				Tags:       []string{"registry.example.com/web:1.0"},
+				BuildArgs:  composetypes.MappingWithEquals{"FOO": &foo},
+				Target:     "prod",
+				NoCache:    true,
+			},
+		},
+	}
+	assert.Check(t, is.DeepEqual(expected, builds, cmp.AllowUnexported(serviceBuild{})))
+
+	builds, err = getServiceBuilds(config, "/project", options.Build{Services: []string{"web"}})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(builds, 1))
+	assert.Check(t, is.Equal("web", builds[0].service))
+}
+
+func TestGetServiceBuildsErrors(t *testing.T) {
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Build: composetypes.BuildConfig{Context: "."}},
+			{Name: "db", Image: "postgres"},
+		},
+	}
+	testCases := []struct {
+		services      []string
+		expectedError string
+	}{
+		{services: nil, expectedError: "service web has a build section but no image to tag the build with"},
+		{services: []string{"db"}, expectedError: "service db does not have a build section"},
+		{services: []string{"cache"}, expectedError: "no such service: cache"},
+	}
+	for _, tc := range testCases {
+		_, err := getServiceBuilds(config, "/project", options.Build{Services: tc.services})
+		assert.Check(t, is.Error(err, tc.expectedError))
+	}
+}
+
+func TestDeployBuildFlagsErrors(t *testing.T) {
+	testCases := []struct {
+		flags         map[string]string
+		expectedError string
+	}{
+		{
+			flags:         map[string]string{"compose-file": "docker-compose.yml", "push": "true"},
+			expectedError: "--push can only be used with --build",
+		},
+		{
+			flags:         map[string]string{"compose-file": "docker-compose.yml", "build": "true", "dry-run": "true"},
+			expectedError: "--build cannot be used with --dry-run",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newDeployCommand(test.NewFakeCli(&fakeClient{}), nil)
+		cmd.SetArgs([]string{"mystack"})
+		cmd.SetOutput(ioutil.Discard)
+		for key, value := range tc.flags {
+			assert.NilError(t, cmd.Flags().Set(key, value))
+		}
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
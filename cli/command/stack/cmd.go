@@ -56,10 +56,13 @@ func NewStackCommand(dockerCli command.Cli) *cobra.Command {
 		defaultHelpFunc(c, args)
 	})
 	cmd.AddCommand(
+		newBuildCommand(dockerCli),
+		newConfigCommand(dockerCli),
 		newDeployCommand(dockerCli, &opts),
 		newListCommand(dockerCli, &opts),
 		newPsCommand(dockerCli, &opts),
 		newRemoveCommand(dockerCli, &opts),
+		newRollbackCommand(dockerCli, &opts),
 		newServicesCommand(dockerCli, &opts),
 	)
 	flags := cmd.PersistentFlags()
@@ -0,0 +1,144 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/loader"
+	"github.com/docker/cli/cli/command/stack/options"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	configFormatYAML = "yaml"
+	configFormatJSON = "json"
+)
+
+func newConfigCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.Config
+
+	cmd := &cobra.Command{
+		Use:   "config [OPTIONS]",
+		Short: "Outputs the final config file, after doing merges and interpolations",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunConfig(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringVar(&opts.Format, "format", configFormatYAML, `Output format ("`+configFormatYAML+`"|"`+configFormatJSON+`")`)
+	flags.BoolVar(&opts.Services, "services", false, "Print the service names, one per line")
+	flags.BoolVar(&opts.Volumes, "volumes", false, "Print the volume names, one per line")
+	flags.BoolVar(&opts.Images, "images", false, "Print the service images, one per line")
+	return cmd
+}
+
+// RunConfig loads the compose files exactly as a stack deploy would, and
+// prints the resulting configuration
+func RunConfig(dockerCli command.Cli, opts options.Config) error {
+	if len(opts.Composefiles) == 0 {
+		return errors.Errorf("Please specify a Compose file (with --compose-file).")
+	}
+	if opts.Format != configFormatYAML && opts.Format != configFormatJSON {
+		return errors.Errorf("invalid format %q: must be one of %q or %q", opts.Format, configFormatYAML, configFormatJSON)
+	}
+	selected := 0
+	for _, set := range []bool{opts.Services, opts.Volumes, opts.Images} {
+		if set {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return errors.New("--services, --volumes and --images cannot be combined")
+	}
+
+	config, err := loader.LoadComposefile(dockerCli, options.Deploy{Composefiles: opts.Composefiles})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.Services:
+		return printLines(dockerCli.Out(), serviceNames(config))
+	case opts.Volumes:
+		return printLines(dockerCli.Out(), volumeNames(config))
+	case opts.Images:
+		return printLines(dockerCli.Out(), serviceImages(config))
+	}
+	return outputConfig(dockerCli.Out(), config, opts.Format)
+}
+
+func outputConfig(out io.Writer, config *composetypes.Config, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if format == configFormatJSON {
+		// Config.MarshalJSON does not indent, so re-indent the whole document
+		var raw json.RawMessage
+		if raw, err = json.Marshal(config); err == nil {
+			data, err = json.MarshalIndent(raw, "", "    ")
+			data = append(data, '\n')
+		}
+	} else {
+		data, err = yaml.Marshal(config)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal config")
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+func serviceNames(config *composetypes.Config) []string {
+	names := make([]string, 0, len(config.Services))
+	for _, service := range config.Services {
+		names = append(names, service.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func volumeNames(config *composetypes.Config) []string {
+	names := make([]string, 0, len(config.Volumes))
+	for name := range config.Volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func serviceImages(config *composetypes.Config) []string {
+	seen := map[string]struct{}{}
+	images := []string{}
+	for _, service := range config.Services {
+		if service.Image == "" {
+			continue
+		}
+		if _, ok := seen[service.Image]; ok {
+			continue
+		}
+		seen[service.Image] = struct{}{}
+		images = append(images, service.Image)
+	}
+	sort.Strings(images)
+	return images
+}
+
+func printLines(out io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
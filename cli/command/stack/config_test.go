@@ -0,0 +1,120 @@
+package stack
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+)
+
+const baseComposefile = `version: "3.7"
+services:
+  web:
+    image: nginx:${TAG}
+  db:
+    image: postgres
+volumes:
+  data: {}
+`
+
+const overrideComposefile = `version: "3.7"
+services:
+  web:
+    environment:
+      FOO: bar
+  worker:
+    image: nginx:${TAG}
+volumes:
+  cache: {}
+`
+
+func TestConfigErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		flags         map[string]string
+		expectedError string
+	}{
+		{
+			args:          []string{"foo"},
+			expectedError: "accepts no argument",
+		},
+		{
+			expectedError: "Please specify a Compose file",
+		},
+		{
+			flags: map[string]string{
+				"compose-file": "docker-compose.yml",
+				"format":       "toml",
+			},
+			expectedError: `invalid format "toml"`,
+		},
+		{
+			flags: map[string]string{
+				"compose-file": "docker-compose.yml",
+				"services":     "true",
+				"images":       "true",
+			},
+			expectedError: "--services, --volumes and --images cannot be combined",
+		},
+	}
+
+	for _, tc := range testCases {
+		cmd := newConfigCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		for key, value := range tc.flags {
+			assert.NilError(t, cmd.Flags().Set(key, value))
+		}
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestConfigMergeAndInterpolate(t *testing.T) {
+	dir := fs.NewDir(t, "stack-config",
+		fs.WithFile("base.yml", baseComposefile),
+		fs.WithFile("override.yml", overrideComposefile),
+	)
+	defer dir.Remove()
+	defer env.Patch(t, "TAG", "1.15")()
+
+	testCases := []struct {
+		flag     string
+		expected string
+	}{
+		{flag: "services", expected: "db\nweb\nworker\n"},
+		{flag: "volumes", expected: "cache\ndata\n"},
+		{flag: "images", expected: "nginx:1.15\npostgres\n"},
+	}
+
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cmd := newConfigCommand(cli)
+		cmd.SetArgs([]string{"-c", dir.Join("base.yml"), "-c", dir.Join("override.yml"), "--" + tc.flag})
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(tc.expected, cli.OutBuffer().String()), tc.flag)
+	}
+}
+
+func TestConfigFormats(t *testing.T) {
+	defer env.Patch(t, "TAG", "1.15")()
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(baseComposefile))))
+	cmd := newConfigCommand(cli)
+	cmd.SetArgs([]string{"-c", "-"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "image: nginx:1.15\n"))
+
+	cli = test.NewFakeCli(&fakeClient{})
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(baseComposefile))))
+	cmd = newConfigCommand(cli)
+	cmd.SetArgs([]string{"-c", "-", "--format", "json"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), `"image": "nginx:1.15"`))
+}
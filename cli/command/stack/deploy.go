@@ -29,6 +29,10 @@ func newDeployCommand(dockerCli command.Cli, common *commonOptions) *cobra.Comma
 				return err
 			}
 
+			if err := validateBuildFlags(opts); err != nil {
+				return err
+			}
+
 			commonOrchestrator := command.OrchestratorSwarm // default for top-level deploy command
 			if common != nil {
 				commonOrchestrator = common.orchestrator
@@ -50,6 +54,12 @@ func newDeployCommand(dockerCli command.Cli, common *commonOptions) *cobra.Comma
 			if err != nil {
 				return err
 			}
+			if opts.Build {
+				buildOpts := options.Build{Composefiles: opts.Composefiles, Push: opts.Push, Quiet: opts.Quiet}
+				if err := RunBuild(dockerCli, config, buildOpts); err != nil {
+					return err
+				}
+			}
 			return RunDeploy(dockerCli, cmd.Flags(), config, common.Orchestrator(), opts)
 		},
 	}
@@ -69,10 +79,35 @@ func newDeployCommand(dockerCli command.Cli, common *commonOptions) *cobra.Comma
 		`Query the registry to resolve image digest and supported platforms ("`+swarm.ResolveImageAlways+`"|"`+swarm.ResolveImageChanged+`"|"`+swarm.ResolveImageNever+`")`)
 	flags.SetAnnotation("resolve-image", "version", []string{"1.30"})
 	flags.SetAnnotation("resolve-image", "swarm", nil)
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Show the changes to the stack without applying them")
+	flags.SetAnnotation("dry-run", "swarm", nil)
+	flags.StringVar(&opts.DryRunFormat, "dry-run-format", "", `Format of the dry-run output ("`+swarm.DryRunFormatJSON+`")`)
+	flags.SetAnnotation("dry-run-format", "swarm", nil)
+	flags.BoolVarP(&opts.Detach, "detach", "d", true, "Exit immediately instead of waiting for the stack services to converge")
+	flags.SetAnnotation("detach", "version", []string{"1.29"})
+	flags.SetAnnotation("detach", "swarm", nil)
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress progress output")
+	flags.SetAnnotation("quiet", "swarm", nil)
+	flags.BoolVar(&opts.Build, "build", false, "Build the images of services with a build section before deploying")
+	flags.BoolVar(&opts.Push, "push", false, "Push the built images before deploying (requires --build)")
 	kubernetes.AddNamespaceFlag(flags)
 	return cmd
 }
 
+func validateBuildFlags(opts options.Deploy) error {
+	switch {
+	case opts.Push && !opts.Build:
+		return errors.New("--push can only be used with --build")
+	case opts.Build && opts.Bundlefile != "":
+		return errors.New("--build can only be used with a Compose file")
+	case opts.Build && opts.DryRun:
+		return errors.New("--build cannot be used with --dry-run")
+	case opts.DryRun && opts.Bundlefile != "":
+		return errors.New("--dry-run can only be used with a Compose file")
+	}
+	return nil
+}
+
 // RunDeploy performs a stack deploy against the specified orchestrator
 func RunDeploy(dockerCli command.Cli, flags *pflag.FlagSet, config *composetypes.Config, commonOrchestrator command.Orchestrator, opts options.Deploy) error {
 	return runOrchestratedCommand(dockerCli, flags, commonOrchestrator,
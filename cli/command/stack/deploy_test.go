@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"gotest.tools/assert"
+	"gotest.tools/fs"
 )
 
 func TestDeployWithEmptyName(t *testing.T) {
@@ -15,3 +18,24 @@ func TestDeployWithEmptyName(t *testing.T) {
 
 	assert.ErrorContains(t, cmd.Execute(), `invalid stack name: "'   '"`)
 }
+
+func TestDeployBundleDryRun(t *testing.T) {
+	bundle := fs.NewFile(t, "bundle", fs.WithContent(`{"Version": "0.1", "Services": {"web": {"Image": "nginx"}}}`))
+	defer bundle.Remove()
+
+	client := &fakeClient{
+		serviceCreateFunc: func(service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			t.Errorf("unexpected service create: %s", service.Name)
+			return types.ServiceCreateResponse{}, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			t.Errorf("unexpected service update: %s", service.Name)
+			return types.ServiceUpdateResponse{}, nil
+		},
+	}
+	cmd := newDeployCommand(test.NewFakeCli(client), nil)
+	cmd.SetArgs([]string{"--bundle-file", bundle.Path(), "--dry-run", "mystack"})
+	cmd.SetOutput(ioutil.Discard)
+
+	assert.ErrorContains(t, cmd.Execute(), "--dry-run can only be used with a Compose file")
+}
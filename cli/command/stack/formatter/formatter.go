@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/spf13/pflag"
 )
 
 const (
@@ -26,6 +27,11 @@ type Context = formatter.Context
 // Format is an alias for formatter.Format
 type Format = formatter.Format
 
+// AddSortFlag adds the --sort flag, see formatter.AddSortFlag
+func AddSortFlag(flags *pflag.FlagSet, p *string) {
+	formatter.AddSortFlag(flags, p)
+}
+
 // Stack contains deployed stack information.
 type Stack struct {
 	// Name is the name of the stack
@@ -131,6 +131,7 @@ func RunServices(dockerCli *KubeCli, opts options.Services) error {
 	servicesCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: service.NewListFormat(format, opts.Quiet),
+		Sort:   opts.Sort,
 	}
 	return service.ListFormatWrite(servicesCtx, services, info)
 }
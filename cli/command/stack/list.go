@@ -28,6 +28,7 @@ func newListCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command
 
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Format, "format", "", "Pretty-print stacks using a Go template")
+	formatter.AddSortFlag(flags, &opts.Sort)
 	flags.StringSliceVar(&opts.Namespaces, "namespace", []string{}, "Kubernetes namespaces to use")
 	flags.SetAnnotation("namespace", "kubernetes", nil)
 	flags.BoolVarP(&opts.AllNamespaces, "all-namespaces", "", false, "List stacks from all Kubernetes namespaces")
@@ -70,6 +71,7 @@ func format(dockerCli command.Cli, opts options.List, orchestrator command.Orche
 	stackCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: formatter.Format(format),
+		Sort:   opts.Sort,
 	}
 	sort.Slice(stacks, func(i, j int) bool {
 		return sortorder.NaturalLess(stacks[i].Name, stacks[j].Name) ||
@@ -36,6 +36,9 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 	}
 
 	unsupportedProperties := loader.GetUnsupportedProperties(dicts...)
+	if opts.Build {
+		unsupportedProperties = removeProperty(unsupportedProperties, "build")
+	}
 	if len(unsupportedProperties) > 0 {
 		fmt.Fprintf(dockerCli.Err(), "Ignoring unsupported options: %s\n\n",
 			strings.Join(unsupportedProperties, ", "))
@@ -49,6 +52,16 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 	return config, nil
 }
 
+func removeProperty(properties []string, name string) []string {
+	result := []string{}
+	for _, property := range properties {
+		if property != name {
+			result = append(result, property)
+		}
+	}
+	return result
+}
+
 func getDictsFrom(configFiles []composetypes.ConfigFile) []map[string]interface{} {
 	dicts := []map[string]interface{}{}
 
@@ -68,6 +81,23 @@ func propertyWarnings(properties map[string]string) string {
 	return strings.Join(msgs, "\n\n")
 }
 
+// WorkingDir returns the directory relative paths of the given compose files
+// are resolved against: the directory of the first file, or the current
+// directory if the compose file is read from stdin.
+func WorkingDir(composefiles []string) (string, error) {
+	if len(composefiles) == 0 {
+		return "", errors.New("no composefile(s)")
+	}
+	if composefiles[0] == "-" && len(composefiles) == 1 {
+		return os.Getwd()
+	}
+	absPath, err := filepath.Abs(composefiles[0])
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(absPath), nil
+}
+
 func getConfigDetails(composefiles []string, stdin io.Reader) (composetypes.ConfigDetails, error) {
 	var details composetypes.ConfigDetails
 
@@ -75,21 +105,11 @@ func getConfigDetails(composefiles []string, stdin io.Reader) (composetypes.Conf
 		return details, errors.New("no composefile(s)")
 	}
 
-	if composefiles[0] == "-" && len(composefiles) == 1 {
-		workingDir, err := os.Getwd()
-		if err != nil {
-			return details, err
-		}
-		details.WorkingDir = workingDir
-	} else {
-		absPath, err := filepath.Abs(composefiles[0])
-		if err != nil {
-			return details, err
-		}
-		details.WorkingDir = filepath.Dir(absPath)
-	}
-
 	var err error
+	details.WorkingDir, err = WorkingDir(composefiles)
+	if err != nil {
+		return details, err
+	}
 	details.ConfigFiles, err = loadConfigFiles(composefiles, stdin)
 	if err != nil {
 		return details, err
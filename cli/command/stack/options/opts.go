@@ -2,6 +2,25 @@ package options
 
 import "github.com/docker/cli/opts"
 
+// Build holds docker stack build options
+type Build struct {
+	Composefiles []string
+	Services     []string
+	Push         bool
+	NoCache      bool
+	Pull         bool
+	Quiet        bool
+}
+
+// Config holds docker stack config options
+type Config struct {
+	Composefiles []string
+	Format       string
+	Services     bool
+	Volumes      bool
+	Images       bool
+}
+
 // Deploy holds docker stack deploy options
 type Deploy struct {
 	Bundlefile       string
@@ -10,11 +29,18 @@ type Deploy struct {
 	ResolveImage     string
 	SendRegistryAuth bool
 	Prune            bool
+	DryRun           bool
+	DryRunFormat     string
+	Detach           bool
+	Quiet            bool
+	Build            bool
+	Push             bool
 }
 
 // List holds docker stack ls options
 type List struct {
 	Format        string
+	Sort          string
 	AllNamespaces bool
 	Namespaces    []string
 }
@@ -34,10 +60,18 @@ type Remove struct {
 	Namespaces []string
 }
 
+// Rollback holds docker stack rollback options
+type Rollback struct {
+	Namespace string
+	Detach    bool
+	Quiet     bool
+}
+
 // Services holds docker stack services options
 type Services struct {
 	Quiet     bool
 	Format    string
+	Sort      string
 	Filter    opts.FilterOpt
 	Namespace string
 }
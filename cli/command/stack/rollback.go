@@ -0,0 +1,43 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/kubernetes"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newRollbackCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Rollback
+
+	cmd := &cobra.Command{
+		Use:   "rollback [OPTIONS] STACK",
+		Short: "Revert every service in the stack to its previous configuration",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			return RunRollback(dockerCli, cmd.Flags(), common.Orchestrator(), opts)
+		},
+		Annotations: map[string]string{"version": "1.31"},
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.Detach, "detach", "d", true, "Exit immediately instead of waiting for the stack services to converge")
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress progress output")
+	return cmd
+}
+
+// RunRollback performs a stack rollback against the specified orchestrator
+func RunRollback(dockerCli command.Cli, flags *pflag.FlagSet, commonOrchestrator command.Orchestrator, opts options.Rollback) error {
+	return runOrchestratedCommand(dockerCli, flags, commonOrchestrator,
+		func() error { return swarm.RunRollback(dockerCli, opts) },
+		func(*kubernetes.KubeCli) error {
+			return errors.New("stack rollback is only supported on a Docker cli with swarm features enabled")
+		})
+}
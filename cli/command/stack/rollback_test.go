@@ -0,0 +1,88 @@
+package stack
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRollbackErrors(t *testing.T) {
+	testCases := []struct {
+		args            []string
+		serviceListFunc func(options types.ServiceListOptions) ([]swarm.Service, error)
+		expectedError   string
+	}{
+		{
+			args:          []string{},
+			expectedError: "requires exactly 1 argument",
+		},
+		{
+			args:          []string{"'   '"},
+			expectedError: `invalid stack name: "'   '"`,
+		},
+		{
+			args: []string{"foo"},
+			serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return nil, errors.New("error getting services")
+			},
+			expectedError: "error getting services",
+		},
+		{
+			args: []string{"foo"},
+			serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return []swarm.Service{}, nil
+			},
+			expectedError: "nothing found in stack: foo",
+		},
+		{
+			args: []string{"foo"},
+			serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return []swarm.Service{{ID: "ID-web", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "foo_web"}}}}, nil
+			},
+			expectedError: "nothing to roll back in stack foo: no service has a previous spec",
+		},
+	}
+
+	for _, tc := range testCases {
+		cmd := newRollbackCommand(test.NewFakeCli(&fakeClient{serviceListFunc: tc.serviceListFunc}), &orchestrator)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	var rolledBack []string
+	client := &fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				{ID: "ID-web", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "foo_web"}}, PreviousSpec: &swarm.ServiceSpec{}},
+				{ID: "ID-db", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "foo_db"}}},
+				{ID: "ID-worker", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "foo_worker"}}, PreviousSpec: &swarm.ServiceSpec{}},
+			}, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			assert.Check(t, is.Equal("previous", options.Rollback))
+			if serviceID == "ID-worker" {
+				return types.ServiceUpdateResponse{}, errors.New("rpc error")
+			}
+			rolledBack = append(rolledBack, serviceID)
+			return types.ServiceUpdateResponse{}, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+	cmd := newRollbackCommand(cli, &orchestrator)
+	cmd.SetArgs([]string{"foo"})
+	cmd.SetOutput(ioutil.Discard)
+
+	assert.Error(t, cmd.Execute(), "Failed to roll back some services from stack foo:\nfoo_worker: rpc error")
+	assert.Check(t, is.DeepEqual([]string{"ID-web"}, rolledBack))
+	assert.Check(t, is.Equal("Skipping service foo_db: service does not have a previous spec\n", cli.ErrBuffer().String()))
+	assert.Check(t, is.Equal("Rolling back service foo_web (id: ID-web)\nRolling back service foo_worker (id: ID-worker)\n", cli.OutBuffer().String()))
+}
@@ -3,6 +3,7 @@ package stack
 import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/formatter"
 	"github.com/docker/cli/cli/command/stack/kubernetes"
 	"github.com/docker/cli/cli/command/stack/options"
 	"github.com/docker/cli/cli/command/stack/swarm"
@@ -29,6 +30,7 @@ func newServicesCommand(dockerCli command.Cli, common *commonOptions) *cobra.Com
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&opts.Format, "format", "", "Pretty-print services using a Go template")
+	formatter.AddSortFlag(flags, &opts.Sort)
 	flags.VarP(&opts.Filter, "filter", "f", "Filter output based on conditions provided")
 	kubernetes.AddNamespaceFlag(flags)
 	return cmd
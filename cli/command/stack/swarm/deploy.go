@@ -28,6 +28,9 @@ func RunDeploy(dockerCli command.Cli, opts options.Deploy, cfg *composetypes.Con
 	if err := validateResolveImageFlag(dockerCli, &opts); err != nil {
 		return err
 	}
+	if err := validateDryRunFlags(opts); err != nil {
+		return err
+	}
 
 	return deployCompose(ctx, dockerCli, opts, cfg)
 }
@@ -46,6 +49,17 @@ func validateResolveImageFlag(dockerCli command.Cli, opts *options.Deploy) error
 	return nil
 }
 
+// validateDryRunFlags validates the opts.DryRunFormat command line option
+func validateDryRunFlags(opts options.Deploy) error {
+	switch {
+	case opts.DryRunFormat != "" && opts.DryRunFormat != DryRunFormatJSON:
+		return errors.Errorf("Invalid option %s for flag --dry-run-format", opts.DryRunFormat)
+	case opts.DryRunFormat != "" && !opts.DryRun:
+		return errors.New("--dry-run-format can only be used with --dry-run")
+	}
+	return nil
+}
+
 // checkDaemonIsSwarmManager does an Info API call to verify that the daemon is
 // a swarm manager. This is necessary because we must create networks before we
 // create services, but the API call for creating a network does not return a
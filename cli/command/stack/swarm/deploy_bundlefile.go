@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/compose/convert"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/pkg/errors"
 )
 
@@ -94,7 +95,14 @@ func DeployBundle(ctx context.Context, dockerCli command.Cli, opts options.Deplo
 	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
 		return err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	serviceIDs, err := deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	if err != nil {
+		return err
+	}
+	if opts.Detach || versions.LessThan(dockerCli.Client().ClientVersion(), "1.29") {
+		return nil
+	}
+	return waitOnServices(ctx, dockerCli, serviceIDs, opts.Quiet)
 }
 
 func loadBundlefile(stderr io.Writer, namespace string, path string) (*bundlefile.Bundlefile, error) {
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
 	apiclient "github.com/docker/docker/client"
 	dockerclient "github.com/docker/docker/client"
 	"github.com/pkg/errors"
@@ -21,6 +22,10 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts options.Depl
 		return err
 	}
 
+	if opts.DryRun {
+		return dryRunCompose(ctx, dockerCli, opts, config)
+	}
+
 	namespace := convert.NewNamespace(opts.Namespace)
 
 	if opts.Prune {
@@ -60,7 +65,14 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts options.Depl
 	if err != nil {
 		return err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	serviceIDs, err := deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	if err != nil {
+		return err
+	}
+	if opts.Detach || versions.LessThan(dockerCli.Client().ClientVersion(), "1.29") {
+		return nil
+	}
+	return waitOnServices(ctx, dockerCli, serviceIDs, opts.Quiet)
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -199,15 +211,16 @@ func deployServices(
 	namespace convert.Namespace,
 	sendAuth bool,
 	resolveImage string,
-) error {
+) (map[string]string, error) {
 	apiClient := dockerCli.Client()
 	out := dockerCli.Out()
 
 	existingServices, err := getStackServices(ctx, apiClient, namespace.Name())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	serviceIDs := make(map[string]string)
 	existingServiceMap := make(map[string]swarm.Service)
 	for _, service := range existingServices {
 		existingServiceMap[service.Spec.Name] = service
@@ -222,7 +235,7 @@ func deployServices(
 			// Retrieve encoded auth token from the image reference
 			encodedAuth, err = command.RetrieveAuthTokenFromImage(ctx, dockerCli, image)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
@@ -256,12 +269,13 @@ func deployServices(
 				updateOpts,
 			)
 			if err != nil {
-				return errors.Wrapf(err, "failed to update service %s", name)
+				return nil, errors.Wrapf(err, "failed to update service %s", name)
 			}
 
 			for _, warning := range response.Warnings {
 				fmt.Fprintln(dockerCli.Err(), warning)
 			}
+			serviceIDs[name] = service.ID
 		} else {
 			fmt.Fprintf(out, "Creating service %s\n", name)
 
@@ -272,10 +286,12 @@ func deployServices(
 				createOpts.QueryRegistry = true
 			}
 
-			if _, err := apiClient.ServiceCreate(ctx, serviceSpec, createOpts); err != nil {
-				return errors.Wrapf(err, "failed to create service %s", name)
+			response, err := apiClient.ServiceCreate(ctx, serviceSpec, createOpts)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create service %s", name)
 			}
+			serviceIDs[name] = response.ID
 		}
 	}
-	return nil
+	return serviceIDs, nil
 }
@@ -0,0 +1,589 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// Actions reported by a dry-run deploy
+const (
+	dryRunCreate    = "create"
+	dryRunUpdate    = "update"
+	dryRunRemove    = "remove"
+	dryRunUnchanged = "unchanged"
+
+	// DryRunFormatJSON prints the dry-run changes as JSON
+	DryRunFormatJSON = "json"
+
+	// pendingID is used as the ID of secrets and configs that do not exist
+	// yet, when converting the services that reference them.
+	pendingID = "<pending>"
+)
+
+// stackChange is a change that deploying a stack would make to an object
+type stackChange struct {
+	Kind   string        `json:"kind"`
+	Name   string        `json:"name"`
+	ID     string        `json:"id,omitempty"`
+	Action string        `json:"action"`
+	Fields []fieldChange `json:"fields,omitempty"`
+}
+
+// fieldChange is a change to a single field of a spec. Path uses the field
+// names of the API types, separated by dots.
+type fieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// dryRunClient is an API client for converting services in a dry run. It
+// reports the secrets and configs that would be created by the deploy as if
+// they already existed.
+type dryRunClient struct {
+	client.APIClient
+	secrets []swarm.SecretSpec
+	configs []swarm.ConfigSpec
+}
+
+func (c *dryRunClient) SecretList(ctx context.Context, opts types.SecretListOptions) ([]swarm.Secret, error) {
+	secrets, err := c.APIClient.SecretList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]struct{}{}
+	for _, secret := range secrets {
+		existing[secret.Spec.Name] = struct{}{}
+	}
+	for _, spec := range c.secrets {
+		if _, ok := existing[spec.Name]; !ok && opts.Filters.ExactMatch("name", spec.Name) {
+			secrets = append(secrets, swarm.Secret{ID: pendingID, Spec: spec})
+		}
+	}
+	return secrets, nil
+}
+
+func (c *dryRunClient) ConfigList(ctx context.Context, opts types.ConfigListOptions) ([]swarm.Config, error) {
+	configs, err := c.APIClient.ConfigList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]struct{}{}
+	for _, config := range configs {
+		existing[config.Spec.Name] = struct{}{}
+	}
+	for _, spec := range c.configs {
+		if _, ok := existing[spec.Name]; !ok && opts.Filters.ExactMatch("name", spec.Name) {
+			configs = append(configs, swarm.Config{ID: pendingID, Spec: spec})
+		}
+	}
+	return configs, nil
+}
+
+// dryRunCompose computes the changes that deployCompose would make to the
+// stack, and prints them without changing anything.
+func dryRunCompose(ctx context.Context, dockerCli command.Cli, opts options.Deploy, config *composetypes.Config) error {
+	changes, err := computeStackChanges(ctx, dockerCli, opts, config)
+	if err != nil {
+		return err
+	}
+	if opts.DryRunFormat == DryRunFormatJSON {
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(changes)
+	}
+	printStackChanges(dockerCli.Out(), opts.Namespace, changes)
+	return nil
+}
+
+func computeStackChanges(ctx context.Context, dockerCli command.Cli, opts options.Deploy, config *composetypes.Config) ([]stackChange, error) {
+	apiClient := dockerCli.Client()
+	namespace := convert.NewNamespace(opts.Namespace)
+
+	serviceNetworks := getServicesDeclaredNetworks(config.Services)
+	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
+	if err := validateExternalNetworks(ctx, apiClient, externalNetworks); err != nil {
+		return nil, err
+	}
+	secrets, err := convert.Secrets(namespace, config.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := convert.Configs(namespace, config.Configs)
+	if err != nil {
+		return nil, err
+	}
+	services, err := convert.Services(namespace, config, &dryRunClient{APIClient: apiClient, secrets: secrets, configs: configs})
+	if err != nil {
+		return nil, err
+	}
+
+	networkChanges, err := diffNetworks(ctx, apiClient, namespace, networks, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+	secretChanges, err := diffSecrets(ctx, apiClient, secrets)
+	if err != nil {
+		return nil, err
+	}
+	configChanges, err := diffConfigs(ctx, apiClient, configs)
+	if err != nil {
+		return nil, err
+	}
+	serviceChanges, err := diffServices(ctx, dockerCli, opts, namespace, services)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []stackChange{}
+	changes = append(changes, networkChanges...)
+	changes = append(changes, secretChanges...)
+	changes = append(changes, configChanges...)
+	return append(changes, serviceChanges...), nil
+}
+
+func diffNetworks(ctx context.Context, apiClient client.APIClient, namespace convert.Namespace, networks map[string]types.NetworkCreate, prune bool) ([]stackChange, error) {
+	existingNetworks, err := getStackNetworks(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]types.NetworkResource)
+	for _, network := range existingNetworks {
+		existing[network.Name] = network
+	}
+
+	changes := []stackChange{}
+	for name := range networks {
+		// existing networks are left as-is by a deploy
+		change := stackChange{Kind: "network", Name: name, Action: dryRunCreate}
+		if network, ok := existing[name]; ok {
+			change.ID = network.ID
+			change.Action = dryRunUnchanged
+		}
+		changes = append(changes, change)
+	}
+	if prune {
+		// a deploy does not remove the networks which are no longer
+		// referenced, they are only reported
+		for name, network := range existing {
+			if _, ok := networks[name]; !ok {
+				changes = append(changes, stackChange{Kind: "network", Name: name, ID: network.ID, Action: dryRunRemove})
+			}
+		}
+	}
+	sortStackChanges(changes)
+	return changes, nil
+}
+
+func diffSecrets(ctx context.Context, apiClient client.APIClient, secrets []swarm.SecretSpec) ([]stackChange, error) {
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+	args := filters.NewArgs()
+	for _, spec := range secrets {
+		args.Add("name", spec.Name)
+	}
+	existingSecrets, err := apiClient.SecretList(ctx, types.SecretListOptions{Filters: args})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]swarm.Secret)
+	for _, secret := range existingSecrets {
+		existing[secret.Spec.Name] = secret
+	}
+
+	changes := []stackChange{}
+	for _, spec := range secrets {
+		secret, ok := existing[spec.Name]
+		if !ok {
+			changes = append(changes, stackChange{Kind: "secret", Name: spec.Name, Action: dryRunCreate})
+			continue
+		}
+		// the API never returns the data of a secret, so only the
+		// annotations can be compared
+		fields, err := diffSpecs(secret.Spec.Annotations, spec.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, newUpdateChange("secret", spec.Name, secret.ID, fields))
+	}
+	sortStackChanges(changes)
+	return changes, nil
+}
+
+func diffConfigs(ctx context.Context, apiClient client.APIClient, configs []swarm.ConfigSpec) ([]stackChange, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	args := filters.NewArgs()
+	for _, spec := range configs {
+		args.Add("name", spec.Name)
+	}
+	existingConfigs, err := apiClient.ConfigList(ctx, types.ConfigListOptions{Filters: args})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]swarm.Config)
+	for _, config := range existingConfigs {
+		existing[config.Spec.Name] = config
+	}
+
+	changes := []stackChange{}
+	for _, spec := range configs {
+		config, ok := existing[spec.Name]
+		if !ok {
+			changes = append(changes, stackChange{Kind: "config", Name: spec.Name, Action: dryRunCreate})
+			continue
+		}
+		fields, err := diffSpecs(config.Spec.Annotations, spec.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(config.Spec.Data, spec.Data) {
+			fields = append(fields, fieldChange{
+				Path: "Data",
+				Old:  fmt.Sprintf("(%d bytes)", len(config.Spec.Data)),
+				New:  fmt.Sprintf("(%d bytes)", len(spec.Data)),
+			})
+		}
+		changes = append(changes, newUpdateChange("config", spec.Name, config.ID, fields))
+	}
+	sortStackChanges(changes)
+	return changes, nil
+}
+
+func diffServices(ctx context.Context, dockerCli command.Cli, opts options.Deploy, namespace convert.Namespace, services map[string]swarm.ServiceSpec) ([]stackChange, error) {
+	apiClient := dockerCli.Client()
+	existingServices, err := getStackServices(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]swarm.Service)
+	for _, service := range existingServices {
+		existing[service.Spec.Name] = service
+	}
+	networkNames, err := stackNetworkNames(ctx, apiClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []stackChange{}
+	for internalName, serviceSpec := range services {
+		name := namespace.Scope(internalName)
+		service, ok := existing[name]
+		if !ok {
+			changes = append(changes, stackChange{Kind: "service", Name: name, Action: dryRunCreate})
+			continue
+		}
+
+		// apply the same adjustments as deployServices, so that fields
+		// that a deploy preserves are not reported as changed
+		serviceSpec = withContainerImage(serviceSpec, resolveServiceImage(ctx, dockerCli, opts, serviceSpec, service))
+		serviceSpec.TaskTemplate.ForceUpdate = service.Spec.TaskTemplate.ForceUpdate
+		// the platforms are resolved by the daemon, along with the image
+		if oldPlacement := service.Spec.TaskTemplate.Placement; oldPlacement != nil && serviceSpec.TaskTemplate.Placement != nil && len(serviceSpec.TaskTemplate.Placement.Platforms) == 0 {
+			placement := *serviceSpec.TaskTemplate.Placement
+			placement.Platforms = oldPlacement.Platforms
+			serviceSpec.TaskTemplate.Placement = &placement
+		}
+
+		oldSpec := normalizeServiceSpec(ctx, apiClient, service.Spec, networkNames)
+		fields, err := diffSpecs(oldSpec, normalizeServiceSpec(ctx, apiClient, serviceSpec, networkNames))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, newUpdateChange("service", name, service.ID, fields))
+	}
+
+	if opts.Prune {
+		for name, service := range existing {
+			if _, ok := services[namespace.Descope(name)]; !ok {
+				changes = append(changes, stackChange{Kind: "service", Name: name, ID: service.ID, Action: dryRunRemove})
+			}
+		}
+	}
+	sortStackChanges(changes)
+	return changes, nil
+}
+
+// resolveServiceImage returns the image that a deploy would set on the
+// existing service. When the deploy queries the registry, the daemon pins the
+// image to the digest of its tag, or leaves it as-is if the registry cannot
+// be reached. Otherwise, the pinned image of the existing service is kept if
+// the image of the service is unchanged.
+func resolveServiceImage(ctx context.Context, dockerCli command.Cli, opts options.Deploy, spec swarm.ServiceSpec, service swarm.Service) string {
+	image := spec.TaskTemplate.ContainerSpec.Image
+	unchanged := image == service.Spec.Labels[convert.LabelImage]
+	if opts.ResolveImage != ResolveImageAlways && (opts.ResolveImage != ResolveImageChanged || unchanged) {
+		if unchanged {
+			return service.Spec.TaskTemplate.ContainerSpec.Image
+		}
+		return image
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return image
+	}
+	encodedAuth := ""
+	if opts.SendRegistryAuth {
+		if encodedAuth, err = command.RetrieveAuthTokenFromImage(ctx, dockerCli, image); err != nil {
+			return image
+		}
+	}
+	distribution, err := dockerCli.Client().DistributionInspect(ctx, image, encodedAuth)
+	if err != nil {
+		return image
+	}
+	pinned, err := reference.WithDigest(reference.TagNameOnly(named), distribution.Descriptor.Digest)
+	if err != nil {
+		return image
+	}
+	return reference.FamiliarString(pinned)
+}
+
+func withContainerImage(spec swarm.ServiceSpec, image string) swarm.ServiceSpec {
+	containerSpec := *spec.TaskTemplate.ContainerSpec
+	containerSpec.Image = image
+	spec.TaskTemplate.ContainerSpec = &containerSpec
+	return spec
+}
+
+// stackNetworkNames returns the names of the networks of the stack, by ID
+func stackNetworkNames(ctx context.Context, apiClient client.APIClient, namespace convert.Namespace) (map[string]string, error) {
+	networks, err := getStackNetworks(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(networks))
+	for _, network := range networks {
+		names[network.ID] = network.Name
+	}
+	return names, nil
+}
+
+// normalizeServiceSpec returns a copy of spec with the defaults that the
+// daemon sets on the fields which are not set by a deploy, and with the
+// networks referenced by name, so that a spec returned by the daemon can be
+// compared with a converted one. The networks which are not part of the stack
+// are looked up by ID, and networkNames is updated with their names.
+func normalizeServiceSpec(ctx context.Context, apiClient client.APIClient, spec swarm.ServiceSpec, networkNames map[string]string) swarm.ServiceSpec {
+	endpointSpec := swarm.EndpointSpec{}
+	if spec.EndpointSpec != nil {
+		endpointSpec = *spec.EndpointSpec
+	}
+	if endpointSpec.Mode == "" {
+		endpointSpec.Mode = swarm.ResolutionModeVIP
+	}
+	spec.EndpointSpec = &endpointSpec
+
+	spec.UpdateConfig = normalizeUpdateConfig(spec.UpdateConfig)
+	spec.RollbackConfig = normalizeUpdateConfig(spec.RollbackConfig)
+
+	taskTemplate := &spec.TaskTemplate
+	resources := swarm.ResourceRequirements{}
+	if taskTemplate.Resources != nil {
+		resources = *taskTemplate.Resources
+	}
+	if resources.Limits == nil {
+		resources.Limits = &swarm.Resources{}
+	}
+	if resources.Reservations == nil {
+		resources.Reservations = &swarm.Resources{}
+	}
+	taskTemplate.Resources = &resources
+
+	if taskTemplate.RestartPolicy != nil && taskTemplate.RestartPolicy.Condition == "" {
+		restartPolicy := *taskTemplate.RestartPolicy
+		restartPolicy.Condition = swarm.RestartPolicyConditionAny
+		taskTemplate.RestartPolicy = &restartPolicy
+	}
+
+	placement := swarm.Placement{}
+	if taskTemplate.Placement != nil {
+		placement = *taskTemplate.Placement
+	}
+	taskTemplate.Placement = &placement
+
+	if taskTemplate.ContainerSpec != nil {
+		containerSpec := *taskTemplate.ContainerSpec
+		if containerSpec.Isolation == "" {
+			containerSpec.Isolation = container.IsolationDefault
+		}
+		if containerSpec.Privileges == nil {
+			containerSpec.Privileges = &swarm.Privileges{}
+		}
+		if containerSpec.DNSConfig == nil {
+			containerSpec.DNSConfig = &swarm.DNSConfig{}
+		}
+		taskTemplate.ContainerSpec = &containerSpec
+		if taskTemplate.Runtime == "" {
+			taskTemplate.Runtime = swarm.RuntimeContainer
+		}
+	}
+
+	networks := make([]swarm.NetworkAttachmentConfig, len(taskTemplate.Networks))
+	for i, network := range taskTemplate.Networks {
+		network.Target = networkName(ctx, apiClient, network.Target, networkNames)
+		networks[i] = network
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Target < networks[j].Target })
+	if len(networks) == 0 {
+		networks = nil
+	}
+	taskTemplate.Networks = networks
+	return spec
+}
+
+// normalizeUpdateConfig sets the default values of the enums of an update or
+// rollback config, which are returned by the daemon when they are not set
+func normalizeUpdateConfig(config *swarm.UpdateConfig) *swarm.UpdateConfig {
+	if config == nil {
+		return nil
+	}
+	normalized := *config
+	if normalized.FailureAction == "" {
+		normalized.FailureAction = swarm.UpdateFailureActionPause
+	}
+	if normalized.Order == "" {
+		normalized.Order = swarm.UpdateOrderStopFirst
+	}
+	return &normalized
+}
+
+// networkName returns the name of the network target, which is either the
+// name or the ID of a network
+func networkName(ctx context.Context, apiClient client.APIClient, target string, networkNames map[string]string) string {
+	if name, ok := networkNames[target]; ok {
+		return name
+	}
+	for _, name := range networkNames {
+		if name == target {
+			return target
+		}
+	}
+	network, err := apiClient.NetworkInspect(ctx, target, types.NetworkInspectOptions{})
+	if err != nil || network.ID != target {
+		return target
+	}
+	networkNames[network.ID] = network.Name
+	return network.Name
+}
+
+func newUpdateChange(kind, name, id string, fields []fieldChange) stackChange {
+	action := dryRunUpdate
+	if len(fields) == 0 {
+		action = dryRunUnchanged
+	}
+	return stackChange{Kind: kind, Name: name, ID: id, Action: action, Fields: fields}
+}
+
+func sortStackChanges(changes []stackChange) {
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+}
+
+// diffSpecs compares the JSON representations of two specs, and returns the
+// fields that differ. Arrays are compared as a whole.
+func diffSpecs(old, new interface{}) ([]fieldChange, error) {
+	oldValue, err := toGenericValue(old)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toGenericValue(new)
+	if err != nil {
+		return nil, err
+	}
+	var fields []fieldChange
+	diffValues("", oldValue, newValue, &fields)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields, nil
+}
+
+func toGenericValue(spec interface{}) (interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal spec")
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func diffValues(path string, old, new interface{}, fields *[]fieldChange) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if !oldIsMap || !newIsMap {
+		if !reflect.DeepEqual(old, new) {
+			*fields = append(*fields, fieldChange{Path: path, Old: old, New: new})
+		}
+		return
+	}
+	for key, oldField := range oldMap {
+		diffValues(joinPath(path, key), oldField, newMap[key], fields)
+	}
+	for key, newField := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			diffValues(joinPath(path, key), nil, newField, fields)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func printStackChanges(out io.Writer, namespace string, changes []stackChange) {
+	fmt.Fprintf(out, "Dry run: no changes will be made to stack %s\n", namespace)
+	count := 0
+	for _, change := range changes {
+		switch change.Action {
+		case dryRunUnchanged:
+			continue
+		case dryRunCreate:
+			fmt.Fprintf(out, "\nWould create %s %s\n", change.Kind, change.Name)
+		default:
+			fmt.Fprintf(out, "\nWould %s %s %s (id: %s)\n", change.Action, change.Kind, change.Name, change.ID)
+		}
+		count++
+		for _, field := range change.Fields {
+			fmt.Fprintf(out, "    %s: %s => %s\n", field.Path, formatFieldValue(field.Old), formatFieldValue(field.New))
+		}
+	}
+	if count == 0 {
+		fmt.Fprintln(out, "\nNothing to do, the stack is up to date")
+	}
+}
+
+func formatFieldValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
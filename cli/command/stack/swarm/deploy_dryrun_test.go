@@ -0,0 +1,234 @@
+package swarm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func dryRunFakeClient(t *testing.T, namespace convert.Namespace, existing ...composetypes.ServiceConfig) *fakeClient {
+	services := []swarm.Service{}
+	for _, service := range existing {
+		spec, err := convert.Service("1.40", namespace, service, nil, nil, nil, nil)
+		assert.NilError(t, err)
+		services = append(services, swarm.Service{ID: "ID-" + spec.Name, Spec: spec})
+	}
+	return &fakeClient{
+		version:  "1.40",
+		networks: []string{objectName(namespace.Name(), "default")},
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		secretListFunc: func(options types.SecretListOptions) ([]swarm.Secret, error) {
+			return nil, nil
+		},
+		configListFunc: func(options types.ConfigListOptions) ([]swarm.Config, error) {
+			return nil, nil
+		},
+	}
+}
+
+func TestComputeStackChanges(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	client := dryRunFakeClient(t, namespace,
+		composetypes.ServiceConfig{Name: "web", Image: "nginx:1.14"},
+		composetypes.ServiceConfig{Name: "cache", Image: "redis"},
+		composetypes.ServiceConfig{Name: "old", Image: "busybox"},
+	)
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Image: "nginx:1.15"},
+			{Name: "cache", Image: "redis"},
+			{Name: "db", Image: "postgres", Networks: map[string]*composetypes.ServiceNetworkConfig{"back": nil}},
+		},
+		Networks: map[string]composetypes.NetworkConfig{"back": {}},
+	}
+
+	changes, err := computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack", Prune: true}, config)
+	assert.NilError(t, err)
+
+	expected := []stackChange{
+		{Kind: "network", Name: "mystack_back", Action: dryRunCreate},
+		{Kind: "network", Name: "mystack_default", ID: "ID-mystack_default", Action: dryRunUnchanged},
+		{Kind: "service", Name: "mystack_cache", ID: "ID-mystack_cache", Action: dryRunUnchanged},
+		{Kind: "service", Name: "mystack_db", Action: dryRunCreate},
+		{Kind: "service", Name: "mystack_old", ID: "ID-mystack_old", Action: dryRunRemove},
+		{
+			Kind:   "service",
+			Name:   "mystack_web",
+			ID:     "ID-mystack_web",
+			Action: dryRunUpdate,
+			Fields: []fieldChange{
+				{Path: "Labels.com.docker.stack.image", Old: "nginx:1.14", New: "nginx:1.15"},
+				{Path: "TaskTemplate.ContainerSpec.Image", Old: "nginx:1.14", New: "nginx:1.15"},
+			},
+		},
+	}
+	assert.Check(t, is.DeepEqual(expected, changes))
+}
+
+const (
+	oldDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	newDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+// daemonService converts service, and sets the fields of its spec as the
+// daemon returns them after a deploy
+func daemonService(t *testing.T, namespace convert.Namespace, service composetypes.ServiceConfig) swarm.Service {
+	spec, err := convert.Service("1.40", namespace, service, nil, nil, nil, nil)
+	assert.NilError(t, err)
+	spec.EndpointSpec = &swarm.EndpointSpec{Mode: swarm.ResolutionModeVIP}
+	spec.TaskTemplate.Resources = &swarm.ResourceRequirements{Limits: &swarm.Resources{}, Reservations: &swarm.Resources{}}
+	spec.TaskTemplate.Runtime = swarm.RuntimeContainer
+	spec.TaskTemplate.ContainerSpec.Isolation = container.IsolationDefault
+	spec.TaskTemplate.ContainerSpec.Image = service.Image + "@" + oldDigest
+	spec.TaskTemplate.Placement.Platforms = []swarm.Platform{{Architecture: "amd64", OS: "linux"}}
+	for i, network := range spec.TaskTemplate.Networks {
+		spec.TaskTemplate.Networks[i].Target = objectID(network.Target)
+	}
+	return swarm.Service{ID: "ID-" + spec.Name, Spec: spec}
+}
+
+func TestComputeStackChangesDaemonDefaults(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	service := composetypes.ServiceConfig{Name: "web", Image: "nginx:1.15"}
+	client := dryRunFakeClient(t, namespace)
+	client.serviceListFunc = func(options types.ServiceListOptions) ([]swarm.Service, error) {
+		return []swarm.Service{daemonService(t, namespace, service)}, nil
+	}
+	config := &composetypes.Config{Services: []composetypes.ServiceConfig{service}}
+
+	for _, resolveImage := range []string{ResolveImageChanged, ResolveImageNever} {
+		changes, err := computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack", ResolveImage: resolveImage}, config)
+		assert.NilError(t, err)
+		assert.Check(t, is.Contains(changes, stackChange{Kind: "service", Name: "mystack_web", ID: "ID-mystack_web", Action: dryRunUnchanged}), resolveImage)
+	}
+}
+
+func TestComputeStackChangesResolveImage(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	service := composetypes.ServiceConfig{Name: "web", Image: "nginx:1.15"}
+	config := &composetypes.Config{Services: []composetypes.ServiceConfig{service}}
+
+	testCases := []struct {
+		doc      string
+		digest   string
+		expected stackChange
+	}{
+		{
+			doc:      "same digest",
+			digest:   oldDigest,
+			expected: stackChange{Kind: "service", Name: "mystack_web", ID: "ID-mystack_web", Action: dryRunUnchanged},
+		},
+		{
+			doc:    "tag moved",
+			digest: newDigest,
+			expected: stackChange{
+				Kind:   "service",
+				Name:   "mystack_web",
+				ID:     "ID-mystack_web",
+				Action: dryRunUpdate,
+				Fields: []fieldChange{
+					{Path: "TaskTemplate.ContainerSpec.Image", Old: "nginx:1.15@" + oldDigest, New: "nginx:1.15@" + newDigest},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			client := dryRunFakeClient(t, namespace)
+			client.serviceListFunc = func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return []swarm.Service{daemonService(t, namespace, service)}, nil
+			}
+			client.distributionInspectFunc = func(image, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+				assert.Check(t, is.Equal("nginx:1.15", image))
+				return registry.DistributionInspect{Descriptor: v1.Descriptor{Digest: digest.Digest(tc.digest)}}, nil
+			}
+
+			changes, err := computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack", ResolveImage: ResolveImageAlways}, config)
+			assert.NilError(t, err)
+			assert.Check(t, is.Contains(changes, tc.expected))
+		})
+	}
+}
+
+func TestComputeStackChangesPruneNetworks(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	client := dryRunFakeClient(t, namespace)
+	client.networks = append(client.networks, objectName("mystack", "old"))
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{{Name: "web", Image: "nginx"}},
+	}
+
+	changes, err := computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack", Prune: true}, config)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(changes, stackChange{Kind: "network", Name: "mystack_old", ID: "ID-mystack_old", Action: dryRunRemove}))
+
+	changes, err = computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack"}, config)
+	assert.NilError(t, err)
+	assert.Check(t, !is.Contains(changes, stackChange{Kind: "network", Name: "mystack_old", ID: "ID-mystack_old", Action: dryRunRemove})().Success())
+}
+
+func TestComputeStackChangesPendingSecret(t *testing.T) {
+	secretFile := fs.NewFile(t, "secret", fs.WithContent("secret"))
+	defer secretFile.Remove()
+
+	namespace := convert.NewNamespace("mystack")
+	client := dryRunFakeClient(t, namespace)
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Image: "nginx", Secrets: []composetypes.ServiceSecretConfig{{Source: "password"}}},
+		},
+		Secrets: map[string]composetypes.SecretConfig{
+			"password": {File: secretFile.Path()},
+		},
+	}
+
+	changes, err := computeStackChanges(context.Background(), test.NewFakeCli(client), options.Deploy{Namespace: "mystack"}, config)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(changes, stackChange{Kind: "secret", Name: "mystack_password", Action: dryRunCreate}))
+	assert.Check(t, is.Contains(changes, stackChange{Kind: "service", Name: "mystack_web", Action: dryRunCreate}))
+}
+
+func TestDryRunComposeOutput(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{{Name: "web", Image: "nginx:1.15"}},
+	}
+
+	cli := test.NewFakeCli(dryRunFakeClient(t, namespace, composetypes.ServiceConfig{Name: "web", Image: "nginx:1.14"}))
+	assert.NilError(t, dryRunCompose(context.Background(), cli, options.Deploy{Namespace: "mystack"}, config))
+	assert.Check(t, is.Equal(`Dry run: no changes will be made to stack mystack
+
+Would update service mystack_web (id: ID-mystack_web)
+    Labels.com.docker.stack.image: "nginx:1.14" => "nginx:1.15"
+    TaskTemplate.ContainerSpec.Image: "nginx:1.14" => "nginx:1.15"
+`, cli.OutBuffer().String()))
+
+	cli = test.NewFakeCli(dryRunFakeClient(t, namespace, composetypes.ServiceConfig{Name: "web", Image: "nginx:1.14"}))
+	assert.NilError(t, dryRunCompose(context.Background(), cli, options.Deploy{Namespace: "mystack", DryRunFormat: DryRunFormatJSON}, config))
+	var changes []stackChange
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &changes))
+	assert.Check(t, is.Len(changes, 2))
+}
+
+func TestValidateDryRunFlags(t *testing.T) {
+	assert.NilError(t, validateDryRunFlags(options.Deploy{DryRun: true}))
+	assert.NilError(t, validateDryRunFlags(options.Deploy{DryRun: true, DryRunFormat: DryRunFormatJSON}))
+	assert.Check(t, is.Error(validateDryRunFlags(options.Deploy{DryRun: true, DryRunFormat: "yaml"}), "Invalid option yaml for flag --dry-run-format"))
+	assert.Check(t, is.Error(validateDryRunFlags(options.Deploy{DryRunFormat: DryRunFormatJSON}), "--dry-run-format can only be used with --dry-run"))
+}
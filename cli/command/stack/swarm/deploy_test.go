@@ -98,7 +98,7 @@ func TestServiceUpdateResolveImageChanged(t *testing.T) {
 				},
 			},
 		}
-		err := deployServices(ctx, client, spec, namespace, false, ResolveImageChanged)
+		_, err := deployServices(ctx, client, spec, namespace, false, ResolveImageChanged)
 		assert.NilError(t, err)
 		assert.Check(t, is.Equal(receivedOptions.QueryRegistry, testcase.expectedQueryRegistry))
 		assert.Check(t, is.Equal(receivedService.TaskTemplate.ContainerSpec.Image, testcase.expectedImage))
@@ -0,0 +1,117 @@
+package swarm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service/progress"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+)
+
+// waitOnServices waits for all the given services to converge at the same
+// time. services maps the name of each service to its ID. The progress of
+// every service is combined into a single progress output, with each line
+// prefixed by the service name.
+func waitOnServices(ctx context.Context, dockerCli command.Cli, services map[string]string, quiet bool) error {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pipeReader, pipeWriter := io.Pipe()
+	combined := &lockedEncoder{enc: json.NewEncoder(pipeWriter)}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures = map[string]error{}
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name, serviceID string) {
+			defer wg.Done()
+			if err := serviceProgress(ctx, dockerCli, name, serviceID, combined); err != nil {
+				mu.Lock()
+				failures[name] = err
+				mu.Unlock()
+			}
+		}(name, services[name])
+	}
+	go func() {
+		wg.Wait()
+		pipeWriter.Close()
+	}()
+
+	if quiet {
+		io.Copy(ioutil.Discard, pipeReader)
+	} else if err := jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil); err != nil {
+		// drain the pipe so that the progress goroutines can finish
+		go io.Copy(ioutil.Discard, pipeReader)
+		return err
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(failures))
+	for _, name := range names {
+		if err, ok := failures[name]; ok {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	return errors.Errorf("failed to converge:\n%s", strings.Join(msgs, "\n"))
+}
+
+// serviceProgress runs progress.ServiceProgress for a single service, and
+// copies its progress messages to out, prefixed with the name of the service.
+func serviceProgress(ctx context.Context, dockerCli command.Cli, name, serviceID string, out *lockedEncoder) error {
+	errChan := make(chan error, 1)
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		errChan <- progress.ServiceProgress(ctx, dockerCli.Client(), serviceID, pipeWriter)
+	}()
+
+	dec := json.NewDecoder(pipeReader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err != io.EOF {
+				go io.Copy(ioutil.Discard, pipeReader)
+			}
+			break
+		}
+		if msg.ID != "" {
+			msg.ID = name + ": " + msg.ID
+		} else if msg.Status != "" {
+			msg.Status = name + ": " + msg.Status
+		}
+		if err := out.Encode(msg); err != nil {
+			go io.Copy(ioutil.Discard, pipeReader)
+			break
+		}
+	}
+	return <-errChan
+}
+
+// lockedEncoder serializes the progress messages of concurrent services
+type lockedEncoder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (e *lockedEncoder) Encode(v interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(v)
+}
@@ -0,0 +1,41 @@
+package swarm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+)
+
+func TestWaitOnServices(t *testing.T) {
+	replicas := uint64(1)
+	updateStates := map[string]swarm.UpdateState{
+		"ID-web":    swarm.UpdateStateCompleted,
+		"ID-worker": swarm.UpdateStateRollbackCompleted,
+		"ID-db":     swarm.UpdateStatePaused,
+	}
+	client := &fakeClient{
+		serviceInspectFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return swarm.Service{
+				ID: serviceID,
+				Spec: swarm.ServiceSpec{
+					Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				},
+				UpdateStatus: &swarm.UpdateStatus{State: updateStates[serviceID], Message: "update failed"},
+			}, nil, nil
+		},
+	}
+	cli := test.NewFakeCli(client)
+
+	err := waitOnServices(context.Background(), cli, map[string]string{"web": "ID-web"}, false)
+	assert.NilError(t, err)
+
+	err = waitOnServices(context.Background(), cli, map[string]string{
+		"web":    "ID-web",
+		"worker": "ID-worker",
+		"db":     "ID-db",
+	}, true)
+	assert.Error(t, err, "failed to converge:\ndb: service update paused: update failed\nworker: service rolled back: update failed")
+}
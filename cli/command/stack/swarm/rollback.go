@@ -0,0 +1,66 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+)
+
+// RunRollback is the swarm implementation of docker stack rollback
+func RunRollback(dockerCli command.Cli, opts options.Rollback) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	services, err := getStackServices(ctx, client, opts.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return errors.Errorf("nothing found in stack: %s", opts.Namespace)
+	}
+
+	var errs []string
+	rolledBack := make(map[string]string)
+	sort.Slice(services, sortServiceByName(services))
+	for _, service := range services {
+		if service.PreviousSpec == nil {
+			fmt.Fprintf(dockerCli.Err(), "Skipping service %s: service does not have a previous spec\n", service.Spec.Name)
+			continue
+		}
+
+		fmt.Fprintf(dockerCli.Out(), "Rolling back service %s (id: %s)\n", service.Spec.Name, service.ID)
+		response, err := client.ServiceUpdate(ctx, service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{
+			Rollback: "previous",
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", service.Spec.Name, err))
+			continue
+		}
+		for _, warning := range response.Warnings {
+			fmt.Fprintln(dockerCli.Err(), warning)
+		}
+		rolledBack[service.Spec.Name] = service.ID
+	}
+
+	if len(rolledBack) == 0 && len(errs) == 0 {
+		return errors.Errorf("nothing to roll back in stack %s: no service has a previous spec", opts.Namespace)
+	}
+
+	if len(rolledBack) > 0 && !opts.Detach && versions.GreaterThanOrEqualTo(client.ClientVersion(), "1.29") {
+		if err := waitOnServices(ctx, dockerCli, rolledBack, opts.Quiet); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("Failed to roll back some services from stack %s:\n%s", opts.Namespace, strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 type listOptions struct {
 	quiet  bool
 	format string
+	sort   string
 	filter opts.FilterOpt
 }
 
@@ -34,6 +35,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display volume names")
 	flags.StringVar(&options.format, "format", "", "Pretty-print volumes using a Go template")
+	formatter.AddSortFlag(flags, &options.sort)
 	flags.VarP(&options.filter, "filter", "f", "Provide filter values (e.g. 'dangling=true')")
 
 	return cmd
@@ -62,6 +64,7 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	volumeCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: formatter.NewVolumeFormat(format, options.quiet),
+		Sort:   options.sort,
 	}
 	return formatter.VolumeWrite(volumeCtx, volumes.Volumes)
 }
@@ -0,0 +1,253 @@
+package loader
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/schema"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/pkg/urlutil"
+	"github.com/pkg/errors"
+)
+
+// extendsConfig is the `extends` section of a service
+type extendsConfig struct {
+	// File is the file containing the extended service, relative to the
+	// file declaring the `extends`. Empty means the same file.
+	File string
+	// Service is the name of the extended service
+	Service string
+}
+
+// extendsFile holds the validated service dicts of a compose file, with
+// their `extends` sections split out.
+type extendsFile struct {
+	filename   string
+	workingDir string
+	services   map[string]interface{}
+	extends    map[string]extendsConfig
+}
+
+// extendsResolver resolves the `extends` sections of services, loading the
+// files referenced by those sections on demand.
+type extendsResolver struct {
+	details types.ConfigDetails
+	opts    *Options
+	files   map[string]*extendsFile
+}
+
+func newExtendsResolver(details types.ConfigDetails, opts *Options) *extendsResolver {
+	return &extendsResolver{
+		details: details,
+		opts:    opts,
+		files:   map[string]*extendsFile{},
+	}
+}
+
+// splitExtends returns a copy of configDict without the `extends` section of
+// its services, so that the remaining dict can be validated and loaded as
+// usual, along with the removed sections.
+func splitExtends(configDict map[string]interface{}) (map[string]interface{}, map[string]extendsConfig, error) {
+	servicesDict, ok := configDict["services"].(map[string]interface{})
+	if !ok {
+		return configDict, nil, nil
+	}
+
+	extends := map[string]extendsConfig{}
+	services := make(map[string]interface{}, len(servicesDict))
+	for name, service := range servicesDict {
+		serviceDict, ok := service.(map[string]interface{})
+		if !ok {
+			// left as-is for the schema validation to report
+			services[name] = service
+			continue
+		}
+		raw, ok := serviceDict["extends"]
+		if !ok {
+			services[name] = serviceDict
+			continue
+		}
+		ext, err := parseExtends(name, raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		extends[name] = ext
+
+		stripped := make(map[string]interface{}, len(serviceDict)-1)
+		for key, value := range serviceDict {
+			if key != "extends" {
+				stripped[key] = value
+			}
+		}
+		services[name] = stripped
+	}
+
+	result := make(map[string]interface{}, len(configDict))
+	for key, value := range configDict {
+		result[key] = value
+	}
+	result["services"] = services
+	return result, extends, nil
+}
+
+func parseExtends(name string, raw interface{}) (extendsConfig, error) {
+	var ext extendsConfig
+	switch value := raw.(type) {
+	case string:
+		ext.Service = value
+	case map[string]interface{}:
+		for key, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return ext, errors.Errorf("services.%s.extends.%s must be a string", name, key)
+			}
+			switch key {
+			case "service":
+				ext.Service = s
+			case "file":
+				ext.File = s
+			default:
+				return ext, errors.Errorf("services.%s.extends contains unsupported option: '%s'", name, key)
+			}
+		}
+	default:
+		return ext, errors.Errorf("services.%s.extends must be a string or a mapping", name)
+	}
+	if ext.Service == "" {
+		return ext, errors.Errorf("services.%s.extends.service is required", name)
+	}
+	return ext, nil
+}
+
+// resolveServices replaces the services of cfg that use `extends` by the
+// result of merging them over the service they extend.
+func (r *extendsResolver) resolveServices(cfg *types.Config, file *extendsFile) error {
+	for i, service := range cfg.Services {
+		if _, ok := file.extends[service.Name]; !ok {
+			continue
+		}
+		resolved, err := r.resolve(file, service.Name, nil)
+		if err != nil {
+			return err
+		}
+		cfg.Services[i] = *resolved
+	}
+	return nil
+}
+
+// resolve loads the service name from file, recursively applying its
+// `extends` section. The service is loaded again on each call, so that the
+// returned config never shares maps or slices with another service.
+func (r *extendsResolver) resolve(file *extendsFile, name string, chain []string) (*types.ServiceConfig, error) {
+	key := file.filename + ":" + name
+	// chain is copied, so that the recursive calls never share its backing
+	// array
+	chain = append(append(make([]string, 0, len(chain)+1), chain...), key)
+	for _, visited := range chain[:len(chain)-1] {
+		if visited == key {
+			return nil, errors.Errorf("circular reference with extends: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	serviceDict, ok := file.services[name].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("cannot extend service %q in %s: service not found", name, file.filename)
+	}
+	service, err := LoadService(name, serviceDict, file.workingDir, r.details.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, ok := file.extends[name]
+	if !ok {
+		return service, nil
+	}
+
+	baseFile := file
+	if ext.File != "" {
+		if baseFile, err = r.loadFile(absPath(file.workingDir, ext.File)); err != nil {
+			return nil, errors.Wrapf(err, "cannot extend service %q", name)
+		}
+	}
+	base, err := r.resolve(baseFile, ext.Service, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	// the build context of a service extended from another file is relative
+	// to that file
+	if baseFile != file && base.Build.Context != "" && !urlutil.IsURL(base.Build.Context) && !urlutil.IsGitURL(base.Build.Context) {
+		base.Build.Context = absPath(baseFile.workingDir, base.Build.Context)
+	}
+
+	// links and dependencies are never inherited from the extended service
+	base.Name = name
+	base.DependsOn = nil
+	base.Links = nil
+
+	merged, err := mergeService(*base, *service)
+	if err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// loadFile reads, interpolates and validates the compose file at filename,
+// which is referenced by the `file` option of an `extends` section.
+func (r *extendsResolver) loadFile(filename string) (*extendsFile, error) {
+	if file, ok := r.files[filename]; ok {
+		return file, nil
+	}
+
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	configDict, err := ParseYAML(bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s", filename)
+	}
+	configDict, extends, err := r.processConfig(configDict, schema.Version(configDict))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid compose file %s", filename)
+	}
+
+	file := &extendsFile{
+		filename:   filename,
+		workingDir: filepath.Dir(filename),
+		services:   getSection(configDict, "services"),
+		extends:    extends,
+	}
+	r.files[filename] = file
+	return file, nil
+}
+
+// processConfig checks a config dict for forbidden properties, interpolates
+// it, splits out the `extends` sections of its services and validates the
+// result against the schema.
+func (r *extendsResolver) processConfig(configDict map[string]interface{}, version string) (map[string]interface{}, map[string]extendsConfig, error) {
+	if err := validateForbidden(configDict); err != nil {
+		return nil, nil, err
+	}
+
+	var err error
+	if !r.opts.SkipInterpolation {
+		configDict, err = interpolateConfig(configDict, *r.opts.Interpolate)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	configDict, extends, err := splitExtends(configDict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !r.opts.SkipValidation {
+		if err := schema.Validate(configDict, version); err != nil {
+			return nil, nil, err
+		}
+	}
+	return configDict, extends, nil
+}
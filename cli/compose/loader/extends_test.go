@@ -0,0 +1,231 @@
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestLoadExtendsSameFile(t *testing.T) {
+	config, err := loadYAML(`
+version: "3.7"
+services:
+  base:
+    image: busybox
+    environment:
+      FOO: foo
+      BAR: bar
+    ports:
+      - 8080:80
+    depends_on:
+      - db
+  web:
+    extends:
+      service: base
+    environment:
+      BAR: baz
+    ports:
+      - 8443:443
+  worker:
+    extends: web
+    command: work
+  db:
+    image: postgres
+`)
+	assert.NilError(t, err)
+
+	services := mapByName(config.Services)
+	assert.Check(t, is.Len(services, 4))
+
+	web := services["web"]
+	assert.Check(t, is.Equal("web", web.Name))
+	assert.Check(t, is.Equal("busybox", web.Image))
+	assert.Check(t, is.DeepEqual(types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("baz")}, web.Environment))
+	assert.Check(t, is.Len(web.Ports, 2))
+	assert.Check(t, is.Len(web.DependsOn, 0))
+
+	worker := services["worker"]
+	assert.Check(t, is.Equal("busybox", worker.Image))
+	assert.Check(t, is.DeepEqual(types.ShellCommand{"work"}, worker.Command))
+	assert.Check(t, is.DeepEqual(types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("baz")}, worker.Environment))
+
+	// the extended service is left untouched
+	base := services["base"]
+	assert.Check(t, is.DeepEqual(types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("bar")}, base.Environment))
+	assert.Check(t, is.Len(base.Ports, 1))
+	assert.Check(t, is.DeepEqual([]string{"db"}, base.DependsOn))
+}
+
+func TestLoadExtendsOtherFile(t *testing.T) {
+	dir := fs.NewDir(t, "extends",
+		fs.WithDir("common",
+			fs.WithFile("base.yml", `
+version: "3.7"
+services:
+  app:
+    extends:
+      service: runtime
+    env_file: app.env
+    volumes:
+      - ./data:/data
+  runtime:
+    image: busybox:${TAG}
+    build: ./runtime
+`),
+			fs.WithFile("app.env", "FOO=foo\n"),
+		),
+	)
+	defer dir.Remove()
+
+	dict, err := ParseYAML([]byte(`
+version: "3.7"
+services:
+  web:
+    extends:
+      file: common/base.yml
+      service: app
+    environment:
+      BAR: bar
+`))
+	assert.NilError(t, err)
+
+	config, err := Load(types.ConfigDetails{
+		WorkingDir:  dir.Path(),
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Config: dict}},
+		Environment: map[string]string{"TAG": "1.29"},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 1))
+
+	web := config.Services[0]
+	assert.Check(t, is.Equal("web", web.Name))
+	assert.Check(t, is.Equal("busybox:1.29", web.Image))
+	assert.Check(t, is.DeepEqual(types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("bar")}, web.Environment))
+	assert.Assert(t, is.Len(web.Volumes, 1))
+	assert.Check(t, is.Equal(filepath.Join(dir.Path(), "common", "data"), web.Volumes[0].Source))
+	assert.Check(t, is.Equal(filepath.Join(dir.Path(), "common", "runtime"), web.Build.Context))
+}
+
+func TestLoadExtendsParentDirectory(t *testing.T) {
+	dir := fs.NewDir(t, "extends",
+		fs.WithFile("other.yml", `
+version: "3.7"
+services:
+  app:
+    image: app
+    build:
+      context: ./app
+      dockerfile: Dockerfile.prod
+  remote:
+    image: remote
+    build: https://github.com/docker/cli.git
+`),
+		fs.WithDir("stack"),
+	)
+	defer dir.Remove()
+
+	dict, err := ParseYAML([]byte(`
+version: "3.7"
+services:
+  web:
+    extends:
+      file: ../other.yml
+      service: app
+  remote:
+    extends:
+      file: ../other.yml
+      service: remote
+`))
+	assert.NilError(t, err)
+
+	config, err := Load(types.ConfigDetails{
+		WorkingDir:  dir.Join("stack"),
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Config: dict}},
+	})
+	assert.NilError(t, err)
+	services := mapByName(config.Services)
+
+	// the relative build context is resolved against the extended file
+	web := services["web"]
+	assert.Check(t, is.Equal(filepath.Join(dir.Path(), "app"), web.Build.Context))
+	assert.Check(t, is.Equal("Dockerfile.prod", web.Build.Dockerfile))
+
+	// URLs are left as-is
+	remote := services["remote"]
+	assert.Check(t, is.Equal("https://github.com/docker/cli.git", remote.Build.Context))
+}
+
+func TestLoadExtendsErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		yaml          string
+		expectedError string
+	}{
+		{
+			name: "cycle",
+			yaml: `
+version: "3.7"
+services:
+  foo:
+    extends: bar
+  bar:
+    extends:
+      service: foo
+`,
+			expectedError: "circular reference with extends",
+		},
+		{
+			name: "missing service",
+			yaml: `
+version: "3.7"
+services:
+  foo:
+    extends: bar
+`,
+			expectedError: `cannot extend service "bar" in filename.yml: service not found`,
+		},
+		{
+			name: "missing file",
+			yaml: `
+version: "3.7"
+services:
+  foo:
+    extends:
+      file: does-not-exist.yml
+      service: bar
+`,
+			expectedError: "does-not-exist.yml",
+		},
+		{
+			name: "unsupported option",
+			yaml: `
+version: "3.7"
+services:
+  foo:
+    extends:
+      service: bar
+      image: busybox
+`,
+			expectedError: "services.foo.extends contains unsupported option: 'image'",
+		},
+		{
+			name: "no service",
+			yaml: `
+version: "3.7"
+services:
+  foo:
+    extends:
+      file: base.yml
+`,
+			expectedError: "services.foo.extends.service is required",
+		},
+	}
+	for _, tc := range testCases {
+		_, err := loadYAML(tc.yaml)
+		assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.name)
+	}
+}
@@ -71,7 +71,7 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 	}
 
 	configs := []*types.Config{}
-	var err error
+	resolver := newExtendsResolver(configDetails, opts)
 
 	for _, file := range configDetails.ConfigFiles {
 		configDict := file.Config
@@ -83,29 +83,26 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 			return nil, errors.Errorf("version mismatched between two composefiles : %v and %v", configDetails.Version, version)
 		}
 
-		if err := validateForbidden(configDict); err != nil {
+		configDict, extends, err := resolver.processConfig(configDict, configDetails.Version)
+		if err != nil {
 			return nil, err
 		}
 
-		if !opts.SkipInterpolation {
-			configDict, err = interpolateConfig(configDict, *opts.Interpolate)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		if !opts.SkipValidation {
-			if err := schema.Validate(configDict, configDetails.Version); err != nil {
-				return nil, err
-			}
-		}
-
 		cfg, err := loadSections(configDict, configDetails)
 		if err != nil {
 			return nil, err
 		}
 		cfg.Filename = file.Filename
 
+		if err := resolver.resolveServices(cfg, &extendsFile{
+			filename:   file.Filename,
+			workingDir: configDetails.WorkingDir,
+			services:   getSection(configDict, "services"),
+			extends:    extends,
+		}); err != nil {
+			return nil, err
+		}
+
 		configs = append(configs, cfg)
 	}
 
@@ -793,8 +793,8 @@ services:
       - /data
     volume_driver: some-driver
   bar:
-    extends:
-      service: foo
+    image: busybox
+    cpu_quota: 50000
 `)
 
 	assert.ErrorType(t, err, reflect.TypeOf(&ForbiddenPropertiesError{}))
@@ -802,7 +802,7 @@ services:
 	props := err.(*ForbiddenPropertiesError).Properties
 	assert.Check(t, is.Len(props, 2))
 	assert.Check(t, is.Contains(props, "volume_driver"))
-	assert.Check(t, is.Contains(props, "extends"))
+	assert.Check(t, is.Contains(props, "cpu_quota"))
 }
 
 func TestInvalidResource(t *testing.T) {
@@ -562,3 +562,15 @@ func TestConfigPath(t *testing.T) {
 
 	SetDir(oldDir)
 }
+
+func TestPluginDirs(t *testing.T) {
+	expected := []string{Path("cli-plugins")}
+	expected = append(expected, defaultSystemPluginDirs...)
+	assert.Check(t, is.DeepEqual(expected, PluginDirs(nil)))
+
+	configFile := &configfile.ConfigFile{
+		CLIPluginsExtraDirs: []string{"foo", "bar"},
+	}
+	expected = append([]string{"foo", "bar"}, expected...)
+	assert.Check(t, is.DeepEqual(expected, PluginDirs(configFile)))
+}
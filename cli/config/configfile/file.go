@@ -20,6 +20,12 @@ const (
 	// URL wasn't saved as part of the config file and it was just
 	// assumed to be this value.
 	defaultIndexServer = "https://index.docker.io/v1/"
+
+	// ContextTLSKeyServerAddress is the server address the key encrypting
+	// the TLS material of contexts is stored under in credentials stores.
+	// It is not a registry, and is never part of the credentials returned by
+	// GetAllCredentials.
+	ContextTLSKeyServerAddress = "docker-context-tls-key"
 )
 
 // ConfigFile ~/.docker/config.json file info
@@ -49,6 +55,13 @@ type ConfigFile struct {
 	Kubernetes           *KubernetesConfig           `json:"kubernetes,omitempty"`
 	CurrentContext       string                      `json:"currentContext,omitempty"`
 	CLIPluginsExtraDirs  []string                    `json:"cliPluginsExtraDirs,omitempty"`
+	Aliases              map[string]string           `json:"aliases,omitempty"`
+	ContextTLSEncryption string                      `json:"contextTLSEncryption,omitempty"`
+	// OverriddenFrom is the configuration this configuration was copied from
+	// by WithOverrides, and OverriddenKeys are the keys it overrides.
+	// Note: for internal use only
+	OverriddenFrom *ConfigFile `json:"-"`
+	OverriddenKeys []string    `json:"-"`
 }
 
 // ProxyConfig contains proxy configuration settings
@@ -166,6 +179,11 @@ func (configFile *ConfigFile) SaveToWriter(writer io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if configFile.OverriddenFrom != nil {
+		if data, err = configFile.withoutOverrides(data); err != nil {
+			return err
+		}
+	}
 	_, err = writer.Write(data)
 	return err
 }
@@ -322,6 +340,7 @@ func (configFile *ConfigFile) GetAllCredentials() (map[string]types.AuthConfig,
 		}
 		auths[registryHostname] = newAuth
 	}
+	delete(auths, ContextTLSKeyServerAddress)
 	return auths, nil
 }
 
@@ -221,6 +221,30 @@ func TestGetAllCredentialsCredsStore(t *testing.T) {
 	assert.Check(t, is.Equal(1, testCredsStore.(*mockNativeStore).GetAllCallCount))
 }
 
+func TestGetAllCredentialsSkipsContextTLSKey(t *testing.T) {
+	configFile := New("filename")
+	configFile.CredentialsStore = "test_creds_store"
+	expectedAuth := types.AuthConfig{
+		Username: "user",
+		Password: "pass",
+	}
+
+	testCredsStore := NewMockNativeStore(map[string]types.AuthConfig{
+		"example.com":              expectedAuth,
+		ContextTLSKeyServerAddress: {Username: "docker-context", Password: "secret"},
+	})
+
+	tmpNewNativeStore := newNativeStore
+	defer func() { newNativeStore = tmpNewNativeStore }()
+	newNativeStore = func(configFile *ConfigFile, helperSuffix string) credentials.Store {
+		return testCredsStore
+	}
+
+	authConfigs, err := configFile.GetAllCredentials()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]types.AuthConfig{"example.com": expectedAuth}, authConfigs))
+}
+
 func TestGetAllCredentialsCredHelper(t *testing.T) {
 	testCredHelperSuffix := "test_cred_helper"
 	testCredHelperRegistryHostname := "credhelper.com"
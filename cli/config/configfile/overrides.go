@@ -0,0 +1,131 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// nonOverridableKeys are the keys of the configuration file which cannot be
+// overridden, because they are not specific to a context or are needed before
+// a context is chosen
+var nonOverridableKeys = map[string]bool{
+	"auths":                true,
+	"currentContext":       true,
+	"contextTLSEncryption": true,
+}
+
+// ValidateOverrides checks that overrides, a JSON object with the same keys as
+// the configuration file, can be layered over a configuration
+func ValidateOverrides(overrides map[string]interface{}) error {
+	var keys []string
+	for key := range overrides {
+		if nonOverridableKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		return errors.Errorf("configuration keys cannot be overridden: %q", keys)
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &ConfigFile{}); err != nil {
+		return errors.Wrap(err, "invalid configuration overrides")
+	}
+	return nil
+}
+
+// WithOverrides returns a copy of the configuration with overrides, a JSON
+// object with the same keys as the configuration file, layered over it.
+// Objects are merged key by key, other values replace the values of the
+// configuration. If the configuration is itself a copy with overrides, the
+// overrides are layered over the configuration it was copied from instead,
+// which is returned as is if there are no overrides.
+//
+// The copy shares the credentials of the configuration, and saving it only
+// saves the values which are not overridden.
+func (configFile *ConfigFile) WithOverrides(overrides map[string]interface{}) (*ConfigFile, error) {
+	if configFile.OverriddenFrom != nil {
+		return configFile.OverriddenFrom.WithOverrides(overrides)
+	}
+	if len(overrides) == 0 {
+		return configFile, nil
+	}
+	if err := ValidateOverrides(overrides); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(configFile)
+	if err != nil {
+		return nil, err
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	mergeOverrides(merged, overrides)
+	if data, err = json.Marshal(merged); err != nil {
+		return nil, err
+	}
+	result := &ConfigFile{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, errors.Wrap(err, "invalid configuration overrides")
+	}
+	if err := checkKubernetesConfiguration(result.Kubernetes); err != nil {
+		return nil, err
+	}
+	result.AuthConfigs = configFile.AuthConfigs
+	result.Filename = configFile.Filename
+	result.OverriddenFrom = configFile
+	for key := range overrides {
+		result.OverriddenKeys = append(result.OverriddenKeys, key)
+	}
+	return result, nil
+}
+
+func mergeOverrides(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeOverrides(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// withoutOverrides replaces the overridden values of the configuration
+// encoded in data by the values of the configuration it was copied from
+func (configFile *ConfigFile) withoutOverrides(data []byte) ([]byte, error) {
+	baseData, err := json.Marshal(configFile.OverriddenFrom)
+	if err != nil {
+		return nil, err
+	}
+	var config, base map[string]json.RawMessage
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(baseData, &base); err != nil {
+		return nil, err
+	}
+	for _, key := range configFile.OverriddenKeys {
+		if value, ok := base[key]; ok {
+			config[key] = value
+		} else {
+			delete(config, key)
+		}
+	}
+	if data, err = json.Marshal(config); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "\t"); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newOverriddenConfigFile() *ConfigFile {
+	configFile := New("config.json")
+	configFile.PsFormat = "table {{.ID}}"
+	configFile.ImagesFormat = "table {{.ID}}"
+	configFile.HTTPHeaders = map[string]string{"X-Local": "local", "X-Shared": "local"}
+	configFile.AuthConfigs["example.com"] = types.AuthConfig{Username: "user", Password: "pass"}
+	return configFile
+}
+
+func TestWithOverrides(t *testing.T) {
+	configFile := newOverriddenConfigFile()
+	result, err := configFile.WithOverrides(map[string]interface{}{
+		"psFormat":    "table {{.Names}}",
+		"HttpHeaders": map[string]interface{}{"X-Shared": "prod", "X-Prod": "prod"},
+		"proxies": map[string]interface{}{
+			"default": map[string]interface{}{"httpProxy": "http://proxy:3128"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(result.PsFormat, "table {{.Names}}"))
+	assert.Check(t, is.Equal(result.ImagesFormat, "table {{.ID}}"))
+	assert.Check(t, is.DeepEqual(result.HTTPHeaders, map[string]string{"X-Local": "local", "X-Shared": "prod", "X-Prod": "prod"}))
+	assert.Check(t, is.DeepEqual(result.Proxies, map[string]ProxyConfig{"default": {HTTPProxy: "http://proxy:3128"}}))
+	assert.Check(t, is.Equal(result.Filename, "config.json"))
+	assert.Check(t, is.DeepEqual(result.AuthConfigs, configFile.AuthConfigs))
+	// the configuration is not modified
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.ID}}"))
+	assert.Check(t, is.Len(configFile.HTTPHeaders, 2))
+
+	// overrides are layered over the original configuration
+	again, err := result.WithOverrides(map[string]interface{}{"imagesFormat": "table {{.Size}}"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(again.PsFormat, "table {{.ID}}"))
+	assert.Check(t, is.Equal(again.ImagesFormat, "table {{.Size}}"))
+	original, err := result.WithOverrides(nil)
+	assert.NilError(t, err)
+	assert.Check(t, original == configFile)
+}
+
+func TestWithOverridesSave(t *testing.T) {
+	configFile := newOverriddenConfigFile()
+	result, err := configFile.WithOverrides(map[string]interface{}{
+		"psFormat":    "table {{.Names}}",
+		"HttpHeaders": map[string]interface{}{"X-Prod": "prod"},
+		"detachKeys":  "ctrl-x",
+	})
+	assert.NilError(t, err)
+	result.AuthConfigs["other.example.com"] = types.AuthConfig{Username: "other", Password: "pass"}
+	result.NodesFormat = "table {{.Hostname}}"
+
+	var buf bytes.Buffer
+	assert.NilError(t, result.SaveToWriter(&buf))
+	saved := New("config.json")
+	assert.NilError(t, saved.LoadFromReader(&buf))
+	assert.Check(t, is.Equal(saved.PsFormat, "table {{.ID}}"))
+	assert.Check(t, is.Equal(saved.DetachKeys, ""))
+	assert.Check(t, is.DeepEqual(saved.HTTPHeaders, map[string]string{"X-Local": "local", "X-Shared": "local"}))
+	// changes to values which are not overridden are saved
+	assert.Check(t, is.Equal(saved.NodesFormat, "table {{.Hostname}}"))
+	assert.Check(t, is.Len(saved.AuthConfigs, 2))
+}
+
+func TestValidateOverrides(t *testing.T) {
+	testCases := []struct {
+		overrides     string
+		expectedError string
+	}{
+		{overrides: `{"psFormat": "table {{.Names}}", "unknown": true}`},
+		{overrides: `{"auths": {}, "currentContext": "other"}`, expectedError: `configuration keys cannot be overridden: ["auths" "currentContext"]`},
+		{overrides: `{"psFormat": 1}`, expectedError: "invalid configuration overrides"},
+	}
+	for _, tc := range testCases {
+		var overrides map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(tc.overrides), &overrides))
+		err := ValidateOverrides(overrides)
+		if tc.expectedError == "" {
+			assert.Check(t, is.Nil(err), tc.overrides)
+		} else {
+			assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.overrides)
+		}
+	}
+}
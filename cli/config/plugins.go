@@ -0,0 +1,32 @@
+package config
+
+import (
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+// PluginDirs returns the directories plugins are looked up in, in descending
+// order of priority: the extra directories set in configFile, the
+// "cli-plugins" directory of the configuration directory and the system wide
+// plugin directories. configFile may be nil.
+func PluginDirs(configFile *configfile.ConfigFile) []string {
+	var pluginDirs []string
+
+	if configFile != nil {
+		pluginDirs = append(pluginDirs, configFile.CLIPluginsExtraDirs...)
+	}
+	pluginDirs = append(pluginDirs, UserPluginDir())
+	pluginDirs = append(pluginDirs, defaultSystemPluginDirs...)
+	return pluginDirs
+}
+
+// UserPluginDir returns the "cli-plugins" directory of the configuration
+// directory, which plugins are installed in for the current user.
+func UserPluginDir() string {
+	return Path("cli-plugins")
+}
+
+// SystemPluginDirs returns the system wide directories plugins are looked up
+// in, which are managed by packages or system administrators.
+func SystemPluginDirs() []string {
+	return append([]string(nil), defaultSystemPluginDirs...)
+}
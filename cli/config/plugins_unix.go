@@ -1,6 +1,6 @@
 // +build !windows
 
-package manager
+package config
 
 var defaultSystemPluginDirs = []string{
 	"/usr/local/lib/docker/cli-plugins", "/usr/local/libexec/docker/cli-plugins",
@@ -31,34 +31,72 @@ type ConnectionHelper struct {
 // GetConnectionHelper returns nil without error when no helper is registered for the scheme.
 // URL is like "ssh://me@server01".
 func GetConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
+	return GetConnectionHelperWithOptions(daemonURL, Options{})
+}
+
+// Options are the options of connection helpers
+type Options struct {
+	// PluginDirs are the directories connection helper plugins are looked
+	// up in. The default plugin directories are used if it is nil.
+	PluginDirs []string
+	// SSH are the options of the ssh command for ssh:// URLs
+	SSH ssh.Options
+}
+
+// GetConnectionHelperWithOptions returns Docker-specific connection helper for the given URL,
+// configured with opts. Like GetConnectionHelper, it returns nil without error for
+// the transports of the Docker client, such as "tcp://", "unix://" or "npipe://".
+// Other schemes than "ssh" are handled by connection helper plugins, and an error
+// is returned if there is no plugin for the scheme.
+func GetConnectionHelperWithOptions(daemonURL string, opts Options) (*ConnectionHelper, error) {
 	u, err := url.Parse(daemonURL)
 	if err != nil {
 		return nil, err
 	}
 	switch scheme := u.Scheme; scheme {
 	case "ssh":
-		sshCmd, sshArgs, err := ssh.New(daemonURL)
-		if err != nil {
+		// validate the URL and options before any connection is made
+		if _, _, err := ssh.NewWithOptions(daemonURL, opts.SSH); err != nil {
 			return nil, err
 		}
 		return &ConnectionHelper{
 			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				return newCommandConn(ctx, sshCmd, sshArgs...)
+				return dialSSH(ctx, daemonURL, opts.SSH)
 			},
 			Host: "http://docker",
 		}, nil
+	case "", "tcp", "unix", "npipe", "fd", "http", "https":
+		return nil, nil
+	default:
+		if !strings.Contains(daemonURL, "://") {
+			// not a URL with a scheme, e.g. "localhost:2375"
+			return nil, nil
+		}
+		return getPluginConnectionHelper(daemonURL, scheme, opts.PluginDirs)
 	}
-	// Future version may support plugins via ~/.docker/config.json. e.g. "dind"
-	// See docker/cli#889 for the previous discussion.
-	return nil, err
 }
 
+// sshExitHint is appended to the error returned when the ssh command exits
+// unexpectedly.
+const sshExitHint = "please make sure the URL is valid, and Docker 18.09 or later is installed on the remote host"
+
 func newCommandConn(ctx context.Context, cmd string, args ...string) (net.Conn, error) {
+	return startCommandConn(ctx, sshExitHint, cmd, args...)
+}
+
+// startCommandConn starts cmd, and returns a connection reading from its
+// stdout and writing to its stdin. exitHint is appended to the error returned
+// when the command exits with an error.
+func startCommandConn(ctx context.Context, exitHint string, cmd string, args ...string) (net.Conn, error) {
 	var (
 		c   commandConn
 		err error
 	)
-	c.cmd = exec.CommandContext(ctx, cmd, args...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.exitHint = exitHint
+	c.cmd = exec.Command(cmd, args...)
 	// we assume that args never contains sensitive information
 	logrus.Debugf("connhelper: starting %s with %v", cmd, args)
 	c.cmd.Env = os.Environ()
@@ -78,12 +116,44 @@ func newCommandConn(ctx context.Context, cmd string, args ...string) (net.Conn,
 	}
 	c.localAddr = dummyAddr{network: "dummy", s: "dummy-0"}
 	c.remoteAddr = dummyAddr{network: "dummy", s: "dummy-1"}
-	return &c, c.cmd.Start()
+	if err := c.cmd.Start(); err != nil {
+		return nil, err
+	}
+	c.dialed = make(chan struct{})
+	go c.killOnCancel(ctx)
+	return &c, nil
+}
+
+// killOnCancel kills the command if ctx is done before the connection is
+// established, that is before anything was read from it. The command is not
+// bound to ctx afterwards, as ctx is usually the context of the first request
+// made on the connection, and the connection can be reused by later requests.
+// It is terminated when the connection is closed.
+func (c *commandConn) killOnCancel(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		c.cmdMutex.Lock()
+		c.dialErr = ctx.Err()
+		c.cmdMutex.Unlock()
+		if err := c.kill(); err != nil {
+			logrus.Debugf("connhelper: %v", err)
+		}
+	case <-c.dialed:
+	}
+}
+
+// setDialed stops watching the context the connection was dialed with.
+func (c *commandConn) setDialed() {
+	c.dialedOnce.Do(func() { close(c.dialed) })
 }
 
 // commandConn implements net.Conn
 type commandConn struct {
 	cmd           *exec.Cmd
+	exitHint      string
+	dialed        chan struct{}
+	dialedOnce    sync.Once
+	dialErr       error // set if the connection was not established before its context was done
 	cmdExited     bool
 	cmdWaitErr    error
 	cmdMutex      sync.Mutex
@@ -131,6 +201,7 @@ func killAndWait(cmd *exec.Cmd) error {
 
 // kill returns nil if the command terminated, regardless to the exit status.
 func (c *commandConn) kill() error {
+	c.setDialed()
 	var werr error
 	c.cmdMutex.Lock()
 	if c.cmdExited {
@@ -157,6 +228,11 @@ func (c *commandConn) onEOF(eof error) error {
 	// when we got EOF, the command is going to be terminated
 	var werr error
 	c.cmdMutex.Lock()
+	if c.dialErr != nil {
+		werr = c.dialErr
+		c.cmdMutex.Unlock()
+		return werr
+	}
 	if c.cmdExited {
 		werr = c.cmdWaitErr
 	} else {
@@ -181,7 +257,7 @@ func (c *commandConn) onEOF(eof error) error {
 	c.stderrMu.Lock()
 	stderr := c.stderr.String()
 	c.stderrMu.Unlock()
-	return errors.Errorf("command %v has exited with %v, please make sure the URL is valid, and Docker 18.09 or later is installed on the remote host: stderr=%s", c.cmd.Args, werr, stderr)
+	return errors.Errorf("command %v has exited with %v, %s: stderr=%s", c.cmd.Args, werr, c.exitHint, stderr)
 }
 
 func ignorableCloseError(err error) bool {
@@ -213,6 +289,9 @@ func (c *commandConn) CloseRead() error {
 
 func (c *commandConn) Read(p []byte) (int, error) {
 	n, err := c.stdout.Read(p)
+	if n > 0 {
+		c.setDialed()
+	}
 	if err == io.EOF {
 		err = c.onEOF(err)
 	}
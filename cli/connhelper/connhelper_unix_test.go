@@ -6,6 +6,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -42,3 +43,38 @@ func TestCommandConnEOFWithoutError(t *testing.T) {
 	assert.Check(t, is.Equal(0, n))
 	assert.Check(t, is.Equal(io.EOF, err))
 }
+
+func TestCommandConnOutlivesDialContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := newCommandConn(ctx, "cat")
+	assert.NilError(t, err)
+	echo := func() {
+		_, err := c.Write([]byte("hello\n"))
+		assert.NilError(t, err)
+		b := make([]byte, len("hello\n"))
+		_, err = io.ReadFull(c, b)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal("hello\n", string(b)))
+	}
+	echo()
+	// the connection can be reused once the request it was dialed for is
+	// done
+	cancel()
+	echo()
+	assert.NilError(t, c.Close())
+
+	_, err = newCommandConn(ctx, "cat")
+	assert.Check(t, is.Equal(context.Canceled, err))
+}
+
+func TestCommandConnDialCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := newCommandConn(ctx, "sleep", "60")
+	assert.NilError(t, err)
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = c.Read(make([]byte, 1))
+	assert.Check(t, is.Equal(context.Canceled, err))
+	assert.Check(t, time.Since(start) < 10*time.Second)
+}
@@ -0,0 +1,106 @@
+package connhelper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/pkg/errors"
+)
+
+// PluginNamePrefix is the prefix of the executables of connection helper
+// plugins. It is followed by the URL scheme the plugin handles, e.g.
+// "docker-connhelper-dind" handles "dind://" URLs.
+const PluginNamePrefix = "docker-connhelper-"
+
+// pluginExitHint is appended to the error returned when a connection helper
+// plugin exits unexpectedly.
+const pluginExitHint = "please make sure the URL is valid, and the connection helper plugin is working"
+
+// errPluginNotFound is the error returned when no connection helper plugin
+// handles a scheme.
+type errPluginNotFound struct {
+	scheme string
+	dirs   []string
+}
+
+func (e errPluginNotFound) NotFound() {}
+
+func (e errPluginNotFound) Error() string {
+	return fmt.Sprintf("no connection helper for scheme %q: install a %s executable in one of %s", e.scheme, pluginName(e.scheme), strings.Join(e.dirs, ", "))
+}
+
+// IsPluginNotFound is true if the given error is due to a connection helper
+// plugin not being found.
+func IsPluginNotFound(err error) bool {
+	_, ok := errors.Cause(err).(errPluginNotFound)
+	return ok
+}
+
+func pluginName(scheme string) string {
+	name := PluginNamePrefix + scheme
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// LookupPlugin returns the path of the connection helper plugin handling
+// scheme, looking for it in pluginDirs in order. The default plugin
+// directories are used if pluginDirs is nil. The error returned satisfies
+// IsPluginNotFound if there is no plugin for scheme.
+func LookupPlugin(scheme string, pluginDirs []string) (string, error) {
+	if pluginDirs == nil {
+		pluginDirs = config.PluginDirs(nil)
+	}
+	// schemes are only made of letters, digits, "+", "-" and ".", but make
+	// sure that a scheme can never be used to escape the plugin directories
+	if scheme == "" || strings.ContainsAny(scheme, `/\`) {
+		return "", errPluginNotFound{scheme: scheme, dirs: pluginDirs}
+	}
+	name := pluginName(scheme)
+	for _, d := range pluginDirs {
+		path := filepath.Join(d, name)
+		fi, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "failed to look up connection helper %s", path)
+		}
+		if fi.IsDir() {
+			continue
+		}
+		return path, nil
+	}
+	return "", errPluginNotFound{scheme: scheme, dirs: pluginDirs}
+}
+
+// getPluginConnectionHelper returns a connection helper starting the plugin
+// handling scheme as
+//
+//	docker-connhelper-<scheme> connect <daemonURL>
+//
+// The plugin must then forward its stdin to the daemon, and the responses of
+// the daemon to its stdout.
+func getPluginConnectionHelper(daemonURL, scheme string, pluginDirs []string) (*ConnectionHelper, error) {
+	path, err := LookupPlugin(scheme, pluginDirs)
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionHelper{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := startCommandConn(ctx, pluginExitHint, path, "connect", daemonURL)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to start connection helper %s", path)
+			}
+			return conn, nil
+		},
+		Host: "http://docker",
+	}, nil
+}
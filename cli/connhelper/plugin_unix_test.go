@@ -0,0 +1,75 @@
+// +build !windows
+
+package connhelper
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestPluginConnectionHelper(t *testing.T) {
+	dir := fs.NewDir(t, "connhelper",
+		fs.WithFile(PluginNamePrefix+"dind", `#!/bin/sh
+[ "$1" = connect ] || exit 1
+echo "connected to $2"
+`, fs.WithMode(0755)),
+		fs.WithFile(PluginNamePrefix+"broken", `#!/bin/sh
+echo "pod not found" >&2
+exit 3
+`, fs.WithMode(0755)),
+	)
+	defer dir.Remove()
+
+	helper, err := GetConnectionHelperWithOptions("dind://pod/container", Options{PluginDirs: []string{dir.Path()}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("http://docker", helper.Host))
+	conn, err := helper.Dialer(context.TODO(), "tcp", "docker:2375")
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("connected to dind://pod/container\n", string(b)))
+
+	helper, err = GetConnectionHelperWithOptions("broken://host", Options{PluginDirs: []string{dir.Path()}})
+	assert.NilError(t, err)
+	conn, err = helper.Dialer(context.TODO(), "tcp", "docker:2375")
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(conn)
+	assert.ErrorContains(t, err, "pod not found")
+	assert.ErrorContains(t, err, "connection helper plugin")
+}
+
+func TestPluginConnectionHelperNotFound(t *testing.T) {
+	dir := fs.NewDir(t, "connhelper")
+	defer dir.Remove()
+
+	_, err := GetConnectionHelperWithOptions("unknown://host", Options{PluginDirs: []string{dir.Path()}})
+	assert.Check(t, IsPluginNotFound(err))
+	assert.ErrorContains(t, err, `no connection helper for scheme "unknown": install a docker-connhelper-unknown executable in one of `+dir.Path())
+}
+
+func TestGetConnectionHelperBuiltinSchemes(t *testing.T) {
+	hosts := []string{
+		"",
+		"tcp://localhost:2375",
+		"unix:///var/run/docker.sock",
+		"npipe:////./pipe/docker_engine",
+		"fd://",
+		"http://localhost:2375",
+		"https://localhost:2376",
+		"localhost:2375",
+	}
+	for _, host := range hosts {
+		helper, err := GetConnectionHelper(host)
+		assert.NilError(t, err, host)
+		assert.Check(t, helper == nil, host)
+
+		helper, err = GetConnectionHelperWithOptions(host, Options{PluginDirs: []string{}})
+		assert.NilError(t, err, host)
+		assert.Check(t, helper == nil, host)
+	}
+}
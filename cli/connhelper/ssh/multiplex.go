@@ -0,0 +1,61 @@
+package ssh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// ControlPath returns the path of the control socket of the master connection
+// shared by the ssh commands run with opts, in controlDir. The name of the
+// socket is made of a hash of opts, so that connections with different
+// identities or jump hosts are not shared, and of the "%C" hash of the
+// connection computed by ssh, 49 characters long once expanded.
+func ControlPath(controlDir string, opts Options) string {
+	b, _ := json.Marshal(opts)
+	sum := sha256.Sum256(b)
+	return filepath.Join(controlDir, hex.EncodeToString(sum[:4])+"-%C")
+}
+
+// MultiplexingArgs returns the options of an ssh command using the master
+// connection listening on controlPath, if it is running, instead of opening a
+// new connection to the remote host.
+func MultiplexingArgs(controlPath string) []string {
+	return []string{
+		"-o", "ControlMaster=no",
+		"-o", "ControlPath=" + controlPath,
+	}
+}
+
+// NewMaster returns cmd and its args, starting in the background a master
+// connection to daemonURL listening on controlPath. The command exits once
+// the master connection is authenticated. The master connection is closed once
+// it has had no client for persist.
+func NewMaster(daemonURL string, opts Options, controlPath string, persist time.Duration) (string, []string, error) {
+	args, err := connectionArgs(daemonURL, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return "ssh", append([]string{
+		"-o", "ControlMaster=yes",
+		"-o", "ControlPath=" + controlPath,
+		"-o", fmt.Sprintf("ControlPersist=%d", int(persist/time.Second)),
+		"-N", "-f",
+	}, args...), nil
+}
+
+// NewMasterCheck returns cmd and its args, exiting successfully if a master
+// connection to daemonURL is listening on controlPath.
+func NewMasterCheck(daemonURL string, opts Options, controlPath string) (string, []string, error) {
+	args, err := connectionArgs(daemonURL, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return "ssh", append([]string{
+		"-o", "ControlPath=" + controlPath,
+		"-O", "check",
+	}, args...), nil
+}
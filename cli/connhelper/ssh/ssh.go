@@ -4,17 +4,53 @@ package ssh
 
 import (
 	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
+// Options are the options of the ssh command which cannot be set in the
+// ssh:// URL.
+type Options struct {
+	// IdentityFile is the private key used to authenticate (ssh -i)
+	IdentityFile string `json:",omitempty"`
+	// ConfigFile is the ssh configuration file used instead of the one of
+	// the user (ssh -F)
+	ConfigFile string `json:",omitempty"`
+	// KnownHostsFile is the known_hosts file the key of the remote host is
+	// strictly checked against
+	KnownHostsFile string `json:",omitempty"`
+	// JumpHost is the host, or the comma separated list of hosts, the
+	// connection goes through (ssh -J)
+	JumpHost string `json:",omitempty"`
+}
+
 // New returns cmd and its args
 func New(daemonURL string) (string, []string, error) {
+	return NewWithOptions(daemonURL, Options{})
+}
+
+// NewWithOptions returns cmd and its args, passing opts to the ssh command
+func NewWithOptions(daemonURL string, opts Options) (string, []string, error) {
+	args, err := connectionArgs(daemonURL, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return "ssh", append(args, []string{"--", "docker", "system", "dial-stdio"}...), nil
+}
+
+// connectionArgs returns the args of the ssh command connecting to daemonURL
+// with opts, without the remote command.
+func connectionArgs(daemonURL string, opts Options) ([]string, error) {
 	sp, err := parseSSHURL(daemonURL)
 	if err != nil {
-		return "", nil, errors.Wrap(err, "SSH host connection is not valid")
+		return nil, errors.Wrap(err, "SSH host connection is not valid")
+	}
+	if strings.HasPrefix(opts.JumpHost, "-") {
+		return nil, errors.Errorf("SSH jump host is not valid: %q", opts.JumpHost)
 	}
-	return "ssh", append(sp.Args(), []string{"--", "docker", "system", "dial-stdio"}...), nil
+	sp.opts = opts
+	return sp.Args(), nil
 }
 
 func parseSSHURL(daemonURL string) (*sshSpec, error) {
@@ -55,10 +91,26 @@ type sshSpec struct {
 	user string
 	host string
 	port string
+	opts Options
 }
 
 func (sp *sshSpec) Args() []string {
 	var args []string
+	if sp.opts.ConfigFile != "" {
+		args = append(args, "-F", sp.opts.ConfigFile)
+	}
+	if sp.opts.IdentityFile != "" {
+		args = append(args, "-i", sp.opts.IdentityFile)
+	}
+	if sp.opts.KnownHostsFile != "" {
+		args = append(args,
+			"-o", "UserKnownHostsFile="+sp.opts.KnownHostsFile,
+			"-o", "StrictHostKeyChecking=yes",
+		)
+	}
+	if sp.opts.JumpHost != "" {
+		args = append(args, "-J", sp.opts.JumpHost)
+	}
 	if sp.user != "" {
 		args = append(args, "-l", sp.user)
 	}
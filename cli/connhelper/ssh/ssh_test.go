@@ -1,7 +1,10 @@
 package ssh
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -62,3 +65,72 @@ func TestParseSSHURL(t *testing.T) {
 		}
 	}
 }
+
+func TestNewWithOptions(t *testing.T) {
+	cmd, args, err := NewWithOptions("ssh://me@foo:10022", Options{
+		IdentityFile:   "/keys/id_prod",
+		ConfigFile:     "/ssh/config",
+		KnownHostsFile: "/ssh/known_hosts",
+		JumpHost:       "bastion.example.com",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ssh", cmd))
+	assert.Check(t, is.DeepEqual([]string{
+		"-F", "/ssh/config",
+		"-i", "/keys/id_prod",
+		"-o", "UserKnownHostsFile=/ssh/known_hosts",
+		"-o", "StrictHostKeyChecking=yes",
+		"-J", "bastion.example.com",
+		"-l", "me",
+		"-p", "10022",
+		"foo",
+		"--", "docker", "system", "dial-stdio",
+	}, args))
+
+	_, _, err = NewWithOptions("ssh://foo", Options{JumpHost: "-oProxyCommand=evil"})
+	assert.Error(t, err, `SSH jump host is not valid: "-oProxyCommand=evil"`)
+}
+
+func TestControlPath(t *testing.T) {
+	dir := "/home/me/.docker/ssh"
+	path := ControlPath(dir, Options{})
+	assert.Check(t, is.Equal(dir, filepath.Dir(path)))
+	assert.Check(t, is.Len(filepath.Base(path), len("01234567-%C")))
+	assert.Check(t, strings.HasSuffix(path, "-%C"))
+	assert.Check(t, is.Equal(path, ControlPath(dir, Options{})))
+
+	for _, opts := range []Options{
+		{IdentityFile: "/home/me/.ssh/id_prod"},
+		{JumpHost: "bastion.example.com"},
+	} {
+		assert.Check(t, path != ControlPath(dir, opts), "%+v", opts)
+	}
+}
+
+func TestMultiplexingArgs(t *testing.T) {
+	assert.Check(t, is.DeepEqual([]string{
+		"-o", "ControlMaster=no",
+		"-o", "ControlPath=/home/me/.docker/ssh/x-%C",
+	}, MultiplexingArgs("/home/me/.docker/ssh/x-%C")))
+
+	cmd, args, err := NewMaster("ssh://me@foo:10022", Options{}, "/home/me/.docker/ssh/x-%C", time.Minute)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("ssh", cmd))
+	assert.Check(t, is.DeepEqual([]string{
+		"-o", "ControlMaster=yes",
+		"-o", "ControlPath=/home/me/.docker/ssh/x-%C",
+		"-o", "ControlPersist=60",
+		"-N", "-f",
+		"-l", "me",
+		"-p", "10022",
+		"foo",
+	}, args))
+
+	_, args, err = NewMasterCheck("ssh://foo", Options{}, "/home/me/.docker/ssh/x-%C")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{
+		"-o", "ControlPath=/home/me/.docker/ssh/x-%C",
+		"-O", "check",
+		"foo",
+	}, args))
+}
@@ -0,0 +1,120 @@
+package connhelper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/connhelper/ssh"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// sshControlPersist is how long a master ssh connection is kept open once it
+// has no client anymore.
+const sshControlPersist = time.Minute
+
+// maxSSHControlDirLen keeps the path of the control sockets, which is made of
+// the directory, a 49 characters name and a 17 characters temporary suffix
+// added by ssh, under the length limit of unix socket paths (104 bytes on
+// macOS, including the terminating NUL).
+const maxSSHControlDirLen = 36
+
+// envNoSSHMultiplexing disables multiplexing when set
+const envNoSSHMultiplexing = "DOCKER_SSH_NO_MULTIPLEXING"
+
+// sshControlDir returns the directory the control sockets of multiplexed ssh
+// connections are created in, or an empty string if ssh connections cannot be
+// multiplexed.
+func sshControlDir() string {
+	if runtime.GOOS == "windows" {
+		// the Windows port of OpenSSH does not support multiplexing
+		return ""
+	}
+	if os.Getenv(envNoSSHMultiplexing) != "" {
+		return ""
+	}
+	dir := config.Path("ssh")
+	if len(dir) > maxSSHControlDirLen || strings.ContainsAny(dir, " \t") {
+		logrus.Debugf("connhelper: not multiplexing ssh connections: %s cannot contain control sockets", dir)
+		return ""
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		logrus.Debugf("connhelper: not multiplexing ssh connections: %v", err)
+		return ""
+	}
+	return dir
+}
+
+// dialSSH starts the ssh command connecting to daemonURL. The connection goes
+// through a master connection shared with the other connections to the same
+// host with the same options, which is started first if it is not running.
+func dialSSH(ctx context.Context, daemonURL string, opts ssh.Options) (net.Conn, error) {
+	sshCmd, sshArgs, err := ssh.NewWithOptions(daemonURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	if dir := sshControlDir(); dir != "" {
+		controlPath := ssh.ControlPath(dir, opts)
+		if err := startSSHMaster(ctx, daemonURL, opts, controlPath); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logrus.Debugf("connhelper: not multiplexing ssh connection: %v", err)
+		} else {
+			sshArgs = append(ssh.MultiplexingArgs(controlPath), sshArgs...)
+		}
+	}
+	return newCommandConn(ctx, sshCmd, sshArgs...)
+}
+
+// startSSHMaster starts the master connection listening on controlPath if it
+// is not running. The master runs in the background, with its output written
+// to a log file next to its control socket, so that the commands using it are
+// not kept waiting for it to close their stderr.
+func startSSHMaster(ctx context.Context, daemonURL string, opts ssh.Options, controlPath string) error {
+	sum := sha256.Sum256([]byte(daemonURL + "\n" + controlPath))
+	base := filepath.Join(filepath.Dir(controlPath), hex.EncodeToString(sum[:8]))
+
+	// the master is not started twice by concurrent connections
+	unlock, err := lockFile(base + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cmd, args, err := ssh.NewMasterCheck(daemonURL, opts, controlPath)
+	if err != nil {
+		return err
+	}
+	if exec.CommandContext(ctx, cmd, args...).Run() == nil {
+		return nil
+	}
+
+	cmd, args, err = ssh.NewMaster(daemonURL, opts, controlPath, sshControlPersist)
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(base+".log", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	master := exec.CommandContext(ctx, cmd, args...)
+	master.Stderr = logFile
+	logrus.Debugf("connhelper: starting ssh master connection with %v", args)
+	if err := master.Run(); err != nil {
+		out, _ := ioutil.ReadFile(logFile.Name())
+		return errors.Errorf("failed to start ssh master connection: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
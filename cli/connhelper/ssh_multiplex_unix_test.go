@@ -0,0 +1,87 @@
+// +build !windows
+
+package connhelper
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/connhelper/ssh"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+)
+
+// fakeSSH logs its arguments, and acts as if a master connection is running
+// on a control path once it has been started.
+const fakeSSH = `#!/bin/sh
+echo "$@" >> "$FAKE_SSH_LOG"
+master="$FAKE_SSH_LOG.$(echo "$*" | sed -n 's|.*ControlPath=[^ ]*/\([0-9a-f]*\)-%C.*|\1|p')"
+case "$*" in
+*"-O check"*) [ -e "$master" ] ;;
+*ControlMaster=yes*) touch "$master" ;;
+*) echo connected ;;
+esac
+`
+
+func dialFakeSSH(t *testing.T, opts ssh.Options) {
+	t.Helper()
+	conn, err := dialSSH(context.Background(), "ssh://me@host", opts)
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("connected\n", string(b)))
+}
+
+func TestDialSSHMultiplexing(t *testing.T) {
+	dir := fs.NewDir(t, "ssh", fs.WithDir("bin", fs.WithFile("ssh", fakeSSH, fs.WithMode(0755))))
+	defer dir.Remove()
+	defer env.Patch(t, "PATH", dir.Join("bin")+":"+os.Getenv("PATH"))()
+	defer env.Patch(t, "FAKE_SSH_LOG", dir.Join("log"))()
+	defer env.Patch(t, envNoSSHMultiplexing, "")()
+	oldDir := config.Dir()
+	config.SetDir(dir.Path())
+	defer config.SetDir(oldDir)
+
+	dialFakeSSH(t, ssh.Options{})
+	dialFakeSSH(t, ssh.Options{})
+	dialFakeSSH(t, ssh.Options{JumpHost: "bastion"})
+
+	b, err := ioutil.ReadFile(dir.Join("log"))
+	assert.NilError(t, err)
+	calls := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Assert(t, is.Len(calls, 8))
+	controlPath := ssh.ControlPath(dir.Join("ssh"), ssh.Options{})
+	assert.Check(t, is.Equal("-o ControlPath="+controlPath+" -O check -l me host", calls[0]))
+	assert.Check(t, is.Equal("-o ControlMaster=yes -o ControlPath="+controlPath+" -o ControlPersist=60 -N -f -l me host", calls[1]))
+	assert.Check(t, is.Equal("-o ControlMaster=no -o ControlPath="+controlPath+" -l me host -- docker system dial-stdio", calls[2]))
+	// the master connection is reused
+	assert.Check(t, is.Equal("-o ControlPath="+controlPath+" -O check -l me host", calls[3]))
+	assert.Check(t, is.Equal("-o ControlMaster=no -o ControlPath="+controlPath+" -l me host -- docker system dial-stdio", calls[4]))
+	// but not by connections with other options
+	otherControlPath := ssh.ControlPath(dir.Join("ssh"), ssh.Options{JumpHost: "bastion"})
+	assert.Check(t, is.Equal("-o ControlPath="+otherControlPath+" -O check -J bastion -l me host", calls[5]))
+	assert.Check(t, is.Equal("-o ControlMaster=yes -o ControlPath="+otherControlPath+" -o ControlPersist=60 -N -f -J bastion -l me host", calls[6]))
+}
+
+func TestDialSSHNoMultiplexing(t *testing.T) {
+	dir := fs.NewDir(t, "ssh", fs.WithDir("bin", fs.WithFile("ssh", fakeSSH, fs.WithMode(0755))))
+	defer dir.Remove()
+	defer env.Patch(t, "PATH", dir.Join("bin")+":"+os.Getenv("PATH"))()
+	defer env.Patch(t, "FAKE_SSH_LOG", dir.Join("log"))()
+	defer env.Patch(t, envNoSSHMultiplexing, "1")()
+	oldDir := config.Dir()
+	config.SetDir(dir.Path())
+	defer config.SetDir(oldDir)
+
+	dialFakeSSH(t, ssh.Options{})
+
+	b, err := ioutil.ReadFile(dir.Join("log"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("-l me host -- docker system dial-stdio\n", string(b)))
+}
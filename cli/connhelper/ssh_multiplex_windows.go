@@ -0,0 +1,11 @@
+package connhelper
+
+import (
+	"github.com/pkg/errors"
+)
+
+// lockFile is not implemented, as ssh connections are not multiplexed on
+// Windows.
+func lockFile(path string) (func(), error) {
+	return nil, errors.New("file locks are not supported on Windows")
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/context"
+	"github.com/pkg/errors"
+)
+
+// Environment variables describing a Docker Engine endpoint, as set by
+// docker-machine and understood by the docker CLI
+const (
+	EnvHost      = "DOCKER_HOST"
+	EnvTLS       = "DOCKER_TLS"
+	EnvTLSVerify = "DOCKER_TLS_VERIFY"
+	EnvCertPath  = "DOCKER_CERT_PATH"
+)
+
+const (
+	caFile   = "ca.pem"
+	certFile = "cert.pem"
+	keyFile  = "key.pem"
+)
+
+// FromEnvironment creates an endpoint from DOCKER_* environment variables, the
+// same way the docker CLI interprets them. When TLS is enabled, TLS material is
+// read from the DOCKER_CERT_PATH directory, or from defaultCertPath if it is
+// not set.
+func FromEnvironment(env map[string]string, defaultCertPath string) (Endpoint, error) {
+	host := env[EnvHost]
+	if host == "" {
+		return Endpoint{}, errors.Errorf("%s is not set", EnvHost)
+	}
+	ep := Endpoint{
+		EndpointMeta: EndpointMeta{
+			Host: host,
+		},
+	}
+	tlsVerify := env[EnvTLSVerify] != ""
+	if !tlsVerify && env[EnvTLS] == "" {
+		return ep, nil
+	}
+	ep.SkipTLSVerify = !tlsVerify
+	certPath := env[EnvCertPath]
+	if certPath == "" {
+		certPath = defaultCertPath
+	}
+	if certPath == "" {
+		return ep, nil
+	}
+	// like the docker CLI, missing files are ignored
+	var paths [3]string
+	for i, name := range []string{caFile, certFile, keyFile} {
+		p := filepath.Join(certPath, name)
+		if _, err := os.Stat(p); err == nil {
+			paths[i] = p
+		}
+	}
+	tlsData, err := context.TLSDataFromFiles(paths[0], paths[1], paths[2])
+	if err != nil {
+		return Endpoint{}, err
+	}
+	ep.TLSData = tlsData
+	return ep, nil
+}
+
+// Environment returns the DOCKER_* environment variables describing the
+// endpoint, as KEY=value pairs. certPath is the directory its TLS material is
+// written to, with the file names expected in DOCKER_CERT_PATH.
+func (c *Endpoint) Environment(certPath string) ([]string, error) {
+	if c.SSH != nil {
+		return nil, errors.New("the ssh options of the endpoint cannot be set with environment variables")
+	}
+	if c.Host == "" {
+		return nil, errors.New("the endpoint has no host")
+	}
+	env := []string{EnvHost + "=" + c.Host}
+	switch {
+	case c.SkipTLSVerify:
+		env = append(env, EnvTLS+"=1")
+	case c.TLSData != nil:
+		env = append(env, EnvTLSVerify+"=1")
+	}
+	if c.TLSData != nil {
+		env = append(env, EnvCertPath+"="+certPath)
+	}
+	return env, nil
+}
+
+// TLSFiles returns the TLS material of the endpoint by file name, with the
+// file names expected in DOCKER_CERT_PATH
+func (c *Endpoint) TLSFiles() map[string][]byte {
+	files := map[string][]byte{}
+	if c.TLSData == nil {
+		return files
+	}
+	for name, data := range map[string][]byte{
+		caFile:   c.TLSData.CA,
+		certFile: c.TLSData.Cert,
+		keyFile:  c.TLSData.Key,
+	} {
+		if data != nil {
+			files[name] = data
+		}
+	}
+	return files
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/context"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestFromEnvironment(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("ca.pem", "the-ca"))
+	defer dir.Remove()
+
+	ep, err := FromEnvironment(map[string]string{EnvHost: "tcp://host:2375", EnvCertPath: dir.Path()}, "")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ep.Host, "tcp://host:2375"))
+	assert.Check(t, !ep.SkipTLSVerify)
+	assert.Check(t, is.Nil(ep.TLSData))
+
+	ep, err = FromEnvironment(map[string]string{EnvHost: "tcp://host:2376", EnvTLS: "1"}, dir.Path())
+	assert.NilError(t, err)
+	assert.Check(t, ep.SkipTLSVerify)
+	assert.Check(t, is.DeepEqual(ep.TLSData, &context.TLSData{CA: []byte("the-ca")}))
+
+	_, err = FromEnvironment(map[string]string{EnvTLSVerify: "1"}, "")
+	assert.Check(t, is.Error(err, "DOCKER_HOST is not set"))
+}
+
+func TestEnvironment(t *testing.T) {
+	ep := Endpoint{
+		EndpointMeta: EndpointMeta{
+			Host:          "tcp://host:2376",
+			SkipTLSVerify: true,
+		},
+		TLSData: &context.TLSData{CA: []byte("the-ca")},
+	}
+	env, err := ep.Environment("/certs")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(env, []string{"DOCKER_HOST=tcp://host:2376", "DOCKER_TLS=1", "DOCKER_CERT_PATH=/certs"}))
+	assert.Check(t, is.DeepEqual(ep.TLSFiles(), map[string][]byte{"ca.pem": []byte("the-ca")}))
+
+	ep.SkipTLSVerify = false
+	ep.TLSData = nil
+	env, err = ep.Environment("")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(env, []string{"DOCKER_HOST=tcp://host:2376"}))
+}
@@ -28,6 +28,9 @@ type Endpoint struct {
 	EndpointMeta
 	TLSData     *context.TLSData
 	TLSPassword string
+	// PluginDirs are the directories connection helper plugins are looked
+	// up in. The default plugin directories are used if it is nil.
+	PluginDirs []string
 }
 
 // WithTLSData loads TLS materials for the endpoint
@@ -94,7 +97,11 @@ func (c *Endpoint) tlsConfig() (*tls.Config, error) {
 func (c *Endpoint) ClientOpts() ([]func(*client.Client) error, error) {
 	var result []func(*client.Client) error
 	if c.Host != "" {
-		helper, err := connhelper.GetConnectionHelper(c.Host)
+		helperOpts := connhelper.Options{PluginDirs: c.PluginDirs}
+		if c.SSH != nil {
+			helperOpts.SSH = *c.SSH
+		}
+		helper, err := connhelper.GetConnectionHelperWithOptions(c.Host, helperOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -114,6 +121,9 @@ func (c *Endpoint) ClientOpts() ([]func(*client.Client) error, error) {
 				// No proxy
 				Transport: &http.Transport{
 					DialContext: helper.Dialer,
+					// every connection is a helper process, stop the
+					// ones which are not used anymore
+					IdleConnTimeout: 30 * time.Second,
 				},
 			}
 			result = append(result,
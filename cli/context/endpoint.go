@@ -1,7 +1,11 @@
 package context
 
+import "github.com/docker/cli/cli/connhelper/ssh"
+
 // EndpointMetaBase contains fields we expect to be common for most context endpoints
 type EndpointMetaBase struct {
 	Host          string `json:",omitempty"`
 	SkipTLSVerify bool
+	// SSH are the options of the ssh command, for ssh:// hosts
+	SSH *ssh.Options `json:",omitempty"`
 }
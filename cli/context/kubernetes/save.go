@@ -13,6 +13,21 @@ func FromKubeConfig(kubeconfig, kubeContext, namespaceOverride string) (Endpoint
 	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
 		&clientcmd.ConfigOverrides{CurrentContext: kubeContext, Context: clientcmdapi.Context{Namespace: namespaceOverride}})
+	return fromClientConfig(cfg)
+}
+
+// FromKubeConfigData creates a Kubernetes endpoint from the content of a
+// Kubeconfig file, using its current context. Relative paths are resolved
+// from the current directory.
+func FromKubeConfigData(data []byte) (Endpoint, error) {
+	kubeConfig, err := clientcmd.Load(data)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	return fromClientConfig(clientcmd.NewDefaultClientConfig(*kubeConfig, &clientcmd.ConfigOverrides{}))
+}
+
+func fromClientConfig(cfg clientcmd.ClientConfig) (Endpoint, error) {
 	ns, _, err := cfg.Namespace()
 	if err != nil {
 		return Endpoint{}, err
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// encryptedTLSDataHeader starts TLS data encrypted by the store. It is
+	// followed by the salt the key was derived with, the nonce and the
+	// encrypted data.
+	encryptedTLSDataHeader = "DOCKER-CONTEXT-TLS-ENCRYPTED-V1\n"
+
+	encryptionSaltSize   = 16
+	encryptionKeySize    = 32
+	encryptionIterations = 100000
+)
+
+// KeyGetter returns the secret the TLS material of contexts is encrypted with,
+// such as a passphrase or a key stored by a credentials helper. It is only
+// called when TLS material is encrypted or decrypted.
+type KeyGetter func() ([]byte, error)
+
+// tlsEncryption encrypts TLS data with AES-256-GCM, with keys derived from the
+// secret returned by getKey and a random salt.
+type tlsEncryption struct {
+	getKey KeyGetter
+
+	mu     sync.Mutex
+	secret []byte
+	// salt is the salt of the data encrypted by this store, so that the key
+	// is only derived once
+	salt []byte
+	// keys are the keys derived from the secret, by salt
+	keys map[string][]byte
+}
+
+func newTLSEncryption(getKey KeyGetter) *tlsEncryption {
+	return &tlsEncryption{
+		getKey: getKey,
+		keys:   map[string][]byte{},
+	}
+}
+
+func (e *tlsEncryption) key(salt []byte) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if key, ok := e.keys[string(salt)]; ok {
+		return key, nil
+	}
+	if e.secret == nil {
+		secret, err := e.getKey()
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("the key of the context store is empty")
+		}
+		e.secret = secret
+	}
+	key := pbkdf2.Key(e.secret, salt, encryptionIterations, encryptionKeySize, sha256.New)
+	e.keys[string(salt)] = key
+	return key, nil
+}
+
+func (e *tlsEncryption) encryptionSalt() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.salt == nil {
+		salt := make([]byte, encryptionSaltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		e.salt = salt
+	}
+	return e.salt, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (e *tlsEncryption) encrypt(data []byte) ([]byte, error) {
+	salt, err := e.encryptionSalt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := e.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	result := append([]byte(encryptedTLSDataHeader), salt...)
+	result = append(result, nonce...)
+	// the header is authenticated along with the data
+	return gcm.Seal(result, nonce, data, []byte(encryptedTLSDataHeader)), nil
+}
+
+func (e *tlsEncryption) decrypt(data []byte) ([]byte, error) {
+	data = data[len(encryptedTLSDataHeader):]
+	if len(data) < encryptionSaltSize {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	salt, data := data[:encryptionSaltSize], data[encryptionSaltSize:]
+	key, err := e.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, data, []byte(encryptedTLSDataHeader))
+	if err != nil {
+		return nil, errors.New("wrong key, or the data has been tampered with")
+	}
+	return plain, nil
+}
+
+func isEncryptedTLSData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedTLSDataHeader))
+}
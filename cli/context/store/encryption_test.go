@@ -0,0 +1,103 @@
+package store
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func staticKey(key string, calls *int) KeyGetter {
+	return func() ([]byte, error) {
+		*calls++
+		return []byte(key), nil
+	}
+}
+
+func TestTLSEncryption(t *testing.T) {
+	testDir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(testDir)
+
+	var calls int
+	s := New(testDir, testCfg, WithTLSEncryption(staticKey("secret", &calls)))
+	assert.NilError(t, s.CreateOrUpdateContext(ContextMetadata{Name: "source"}))
+	assert.NilError(t, s.ResetContextEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{
+			"cert.pem": []byte("the-cert"),
+			"key.pem":  []byte("the-key"),
+		},
+	}))
+
+	onDisk, err := ioutil.ReadFile(filepath.Join(s.GetContextStorageInfo("source").TLSPath, "ep1", "key.pem"))
+	assert.NilError(t, err)
+	assert.Check(t, isEncryptedTLSData(onDisk))
+	assert.Check(t, !bytes.Contains(onDisk, []byte("the-key")))
+
+	data, err := s.GetContextTLSData("source", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("the-key", string(data)))
+	data, err = s.GetContextTLSData("source", "ep1", "cert.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("the-cert", string(data)))
+	assert.Check(t, is.Equal(1, calls))
+
+	// reading the data back with another store derives the key again
+	calls = 0
+	data, err = New(testDir, testCfg, WithTLSEncryption(staticKey("secret", &calls))).GetContextTLSData("source", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("the-key", string(data)))
+	assert.Check(t, is.Equal(1, calls))
+
+	_, err = New(testDir, testCfg, WithTLSEncryption(staticKey("wrong", &calls))).GetContextTLSData("source", "ep1", "key.pem")
+	assert.Error(t, err, "failed to decrypt tls data for source/ep1/key.pem: wrong key, or the data has been tampered with")
+
+	_, err = New(testDir, testCfg).GetContextTLSData("source", "ep1", "key.pem")
+	assert.Error(t, err, "tls data for source/ep1/key.pem is encrypted, but encryption is not enabled for the context store")
+}
+
+func TestTLSEncryptionReadsUnencryptedData(t *testing.T) {
+	testDir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(testDir)
+
+	assert.NilError(t, New(testDir, testCfg).ResetContextEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("the-key")},
+	}))
+	var calls int
+	data, err := New(testDir, testCfg, WithTLSEncryption(staticKey("secret", &calls))).GetContextTLSData("source", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("the-key", string(data)))
+	assert.Check(t, is.Equal(0, calls))
+}
+
+func TestTLSEncryptionExportImport(t *testing.T) {
+	testDir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(testDir)
+
+	var calls int
+	source := New(filepath.Join(testDir, "source"), testCfg, WithTLSEncryption(staticKey("secret", &calls)))
+	assert.NilError(t, source.CreateOrUpdateContext(ContextMetadata{Name: "source"}))
+	assert.NilError(t, source.ResetContextEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("the-key")},
+	}))
+
+	// exported TLS material is decrypted, and encrypted again by the store
+	// it is imported in
+	dest := New(filepath.Join(testDir, "dest"), testCfg, WithTLSEncryption(staticKey("other-secret", &calls)))
+	r := Export("source", source)
+	defer r.Close()
+	assert.NilError(t, Import("dest", dest, r))
+	data, err := dest.GetContextTLSData("dest", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("the-key", string(data)))
+
+	onDisk, err := ioutil.ReadFile(filepath.Join(dest.GetContextStorageInfo("dest").TLSPath, "ep1", "key.pem"))
+	assert.NilError(t, err)
+	assert.Check(t, isEncryptedTLSData(onDisk))
+}
@@ -51,13 +51,25 @@ type ContextTLSData struct {
 	Endpoints map[string]EndpointTLSData
 }
 
+// Option is a functional option of a store
+type Option func(*store)
+
+// WithTLSEncryption makes the store encrypt the TLS material of contexts with
+// a key derived from the secret returned by getKey. TLS material is decrypted
+// in memory only, when it is read. Unencrypted TLS material can still be read.
+func WithTLSEncryption(getKey KeyGetter) Option {
+	return func(s *store) {
+		s.tls.encryption = newTLSEncryption(getKey)
+	}
+}
+
 // New creates a store from a given directory.
 // If the directory does not exist or is empty, initialize it
-func New(dir string, cfg Config) Store {
+func New(dir string, cfg Config, opts ...Option) Store {
 	metaRoot := filepath.Join(dir, metadataDir)
 	tlsRoot := filepath.Join(dir, tlsDir)
 
-	return &store{
+	s := &store{
 		meta: &metadataStore{
 			root:   metaRoot,
 			config: cfg,
@@ -66,6 +78,10 @@ func New(dir string, cfg Config) Store {
 			root: tlsRoot,
 		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type store struct {
@@ -314,6 +330,31 @@ func (e *tlsDataDoesNotExistError) setContext(name string) {
 // NotFound satisfies interface github.com/docker/docker/errdefs.ErrNotFound
 func (e *tlsDataDoesNotExistError) NotFound() {}
 
+type tlsDataEncryptedError struct {
+	context, endpoint, file string
+}
+
+func (e *tlsDataEncryptedError) Error() string {
+	return fmt.Sprintf("tls data for %s/%s/%s is encrypted, but encryption is not enabled for the context store", e.context, e.endpoint, e.file)
+}
+
+func (e *tlsDataEncryptedError) setContext(name string) {
+	e.context = name
+}
+
+type tlsDataDecryptionError struct {
+	context, endpoint, file string
+	err                     error
+}
+
+func (e *tlsDataDecryptionError) Error() string {
+	return fmt.Sprintf("failed to decrypt tls data for %s/%s/%s: %v", e.context, e.endpoint, e.file, e.err)
+}
+
+func (e *tlsDataDecryptionError) setContext(name string) {
+	e.context = name
+}
+
 // IsErrContextDoesNotExist checks if the given error is a "context does not exist" condition
 func IsErrContextDoesNotExist(err error) bool {
 	_, ok := err.(*contextDoesNotExistError)
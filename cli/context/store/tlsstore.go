@@ -4,12 +4,16 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+
+	"github.com/pkg/errors"
 )
 
 const tlsDir = "tls"
 
 type tlsStore struct {
 	root string
+	// encryption encrypts the data written to the store if it is set
+	encryption *tlsEncryption
 }
 
 func (s *tlsStore) contextDir(id contextdir) string {
@@ -33,6 +37,12 @@ func (s *tlsStore) createOrUpdate(contextID contextdir, endpointName, filename s
 	if err := os.MkdirAll(epdir, 0700); err != nil {
 		return err
 	}
+	if s.encryption != nil {
+		var err error
+		if data, err = s.encryption.encrypt(data); err != nil {
+			return errors.Wrapf(err, "failed to encrypt tls data for %s/%s", endpointName, filename)
+		}
+	}
 	return ioutil.WriteFile(s.filePath(contextID, endpointName, filename), data, 0600)
 }
 
@@ -41,6 +51,16 @@ func (s *tlsStore) getData(contextID contextdir, endpointName, filename string)
 	if err != nil {
 		return nil, convertTLSDataDoesNotExist(endpointName, filename, err)
 	}
+	if !isEncryptedTLSData(data) {
+		return data, nil
+	}
+	if s.encryption == nil {
+		return nil, &tlsDataEncryptedError{endpoint: endpointName, file: filename}
+	}
+	data, err = s.encryption.decrypt(data)
+	if err != nil {
+		return nil, &tlsDataDecryptionError{endpoint: endpointName, file: filename, err: err}
+	}
 	return data, nil
 }
 
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	cliconfig "github.com/docker/cli/cli/config"
+	shellwords "github.com/mattn/go-shellwords"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// processAliases expands the command of args if it is one of the aliases
+// defined in the config file. The arguments following the alias are appended
+// to its expansion, which may itself start with another alias. Aliases never
+// shadow built-in commands.
+func processAliases(root *cobra.Command, args []string) ([]string, error) {
+	pos, configDir := findAliasCommand(root.Flags(), args)
+	if pos < 0 || isBuiltinCommand(root, args[pos]) {
+		return args, nil
+	}
+	if configDir == "" {
+		configDir = cliconfig.Dir()
+	}
+	configFile, err := cliconfig.Load(configDir)
+	if err != nil || len(configFile.Aliases) == 0 {
+		// errors loading the config file are reported when initializing
+		// the cli
+		return args, nil
+	}
+
+	cmdArgs := args[pos:]
+	var chain []string
+	for {
+		name := cmdArgs[0]
+		alias, ok := configFile.Aliases[name]
+		if !ok || isBuiltinCommand(root, name) {
+			break
+		}
+		chain = append(chain, name)
+		for _, expanded := range chain[:len(chain)-1] {
+			if expanded == name {
+				return nil, errors.Errorf("alias %q is recursive: %s", chain[0], strings.Join(chain, " -> "))
+			}
+		}
+
+		words, err := shellwords.Parse(alias)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid alias %q", name)
+		}
+		if len(words) == 0 {
+			return nil, errors.Errorf("invalid alias %q: alias is empty", name)
+		}
+		cmdArgs = append(words, cmdArgs[1:]...)
+	}
+
+	return append(append([]string{}, args[:pos]...), cmdArgs...), nil
+}
+
+// findAliasCommand returns the position of the command in args, skipping the
+// top-level flags and their values, along with the value of the --config
+// flag. The position is -1 if args has no command.
+func findAliasCommand(flags *pflag.FlagSet, args []string) (int, string) {
+	var configDir string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1, configDir
+		case !strings.HasPrefix(arg, "-") || arg == "-":
+			return i, configDir
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := arg[2:], "", false
+			if j := strings.Index(name, "="); j >= 0 {
+				name, value, hasValue = name[:j], name[j+1:], true
+			}
+			flag := flags.Lookup(name)
+			if flag == nil || flag.NoOptDefVal != "" {
+				continue
+			}
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			if name == "config" {
+				configDir = value
+			}
+		default:
+			// shorthand flags can be combined, e.g. -Dl debug
+			for j := 1; j < len(arg); j++ {
+				flag := flags.ShorthandLookup(arg[j : j+1])
+				if flag == nil || flag.NoOptDefVal != "" {
+					continue
+				}
+				if j == len(arg)-1 {
+					i++
+				}
+				break
+			}
+		}
+	}
+	return -1, configDir
+}
+
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	if name == "help" {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		// aliases take precedence over CLI plugins
+		if cmd.Annotations[cli.CommandAnnotationAlias] != "" || cmd.Annotations[pluginmanager.CommandAnnotationPlugin] == "true" {
+			continue
+		}
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// addAliasCommandStubs adds a stub command for every alias defined in the
+// config file, so that they are listed in the help output.
+func addAliasCommandStubs(dockerCli command.Cli, root *cobra.Command) {
+	existing := map[string]bool{}
+	for _, cmd := range root.Commands() {
+		if cmd.Annotations[cli.CommandAnnotationAlias] != "" {
+			existing[cmd.Name()] = true
+		}
+	}
+	for name, alias := range dockerCli.ConfigFile().Aliases {
+		if existing[name] || isBuiltinCommand(root, name) {
+			continue
+		}
+		root.AddCommand(&cobra.Command{
+			Use:         name,
+			Short:       "Alias for \"docker " + alias + "\"",
+			Run:         func(_ *cobra.Command, _ []string) {},
+			Annotations: map[string]string{cli.CommandAnnotationAlias: alias},
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+const aliasesConfig = `{
+	"aliases": {
+		"lsa": "ps -a --format '{{.Names}}\t{{.Status}}'",
+		"rmi-dangling": "image prune -f",
+		"up": "lsa --no-trunc",
+		"ps": "ps -a",
+		"hello": "helloworld --who=alias",
+		"loop": "loop2 foo",
+		"loop2": "loop",
+		"empty": "",
+		"invalid": "ps '"
+	}
+}`
+
+func TestProcessAliases(t *testing.T) {
+	dir := fs.NewDir(t, "aliases", fs.WithFile("config.json", aliasesConfig))
+	defer dir.Remove()
+
+	testCases := []struct {
+		doc      string
+		args     []string
+		expected []string
+	}{
+		{
+			doc:      "no alias",
+			args:     []string{"--config", dir.Path(), "images", "-a"},
+			expected: []string{"--config", dir.Path(), "images", "-a"},
+		},
+		{
+			doc:      "alias arguments are appended",
+			args:     []string{"--config", dir.Path(), "rmi-dangling", "--filter", "until=24h"},
+			expected: []string{"--config", dir.Path(), "image", "prune", "-f", "--filter", "until=24h"},
+		},
+		{
+			doc:      "quoted arguments",
+			args:     []string{"--config=" + dir.Path(), "lsa"},
+			expected: []string{"--config=" + dir.Path(), "ps", "-a", "--format", "{{.Names}}\t{{.Status}}"},
+		},
+		{
+			doc:      "alias of an alias, after global flags",
+			args:     []string{"-l", "debug", "-D", "--config", dir.Path(), "-H", "tcp://host:2375", "up"},
+			expected: []string{"-l", "debug", "-D", "--config", dir.Path(), "-H", "tcp://host:2375", "ps", "-a", "--format", "{{.Names}}\t{{.Status}}", "--no-trunc"},
+		},
+		{
+			doc:      "builtin commands are never shadowed",
+			args:     []string{"--config", dir.Path(), "ps"},
+			expected: []string{"--config", dir.Path(), "ps"},
+		},
+		{
+			doc:      "aliases can run plugins",
+			args:     []string{"--config", dir.Path(), "hello"},
+			expected: []string{"--config", dir.Path(), "helloworld", "--who=alias"},
+		},
+		{
+			doc:      "no command",
+			args:     []string{"--config", dir.Path()},
+			expected: []string{"--config", dir.Path()},
+		},
+	}
+	for _, tc := range testCases {
+		args, err := processAliases(newDockerCommand(&command.DockerCli{}), tc.args)
+		assert.NilError(t, err, tc.doc)
+		assert.Check(t, is.DeepEqual(tc.expected, args), tc.doc)
+	}
+}
+
+func TestProcessAliasesErrors(t *testing.T) {
+	dir := fs.NewDir(t, "aliases", fs.WithFile("config.json", aliasesConfig))
+	defer dir.Remove()
+
+	testCases := []struct {
+		alias         string
+		expectedError string
+	}{
+		{alias: "loop", expectedError: `alias "loop" is recursive: loop -> loop2 -> loop`},
+		{alias: "empty", expectedError: `invalid alias "empty": alias is empty`},
+		{alias: "invalid", expectedError: `invalid alias "invalid"`},
+	}
+	for _, tc := range testCases {
+		_, err := processAliases(newDockerCommand(&command.DockerCli{}), []string{"--config", dir.Path(), tc.alias})
+		assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.alias)
+	}
+}
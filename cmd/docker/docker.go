@@ -10,6 +10,8 @@ import (
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/commands"
+	contextcmd "github.com/docker/cli/cli/command/context"
+	cliconfig "github.com/docker/cli/cli/config"
 	cliflags "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/cli/version"
 	"github.com/docker/docker/api/types/versions"
@@ -164,6 +166,7 @@ func setHelpFunc(dockerCli *command.DockerCli, cmd *cobra.Command, flags *pflag.
 			ccmd.Println(err)
 			return
 		}
+		addAliasCommandStubs(dockerCli, ccmd.Root())
 
 		if len(args) >= 1 {
 			err := tryRunPluginHelp(dockerCli, ccmd, args)
@@ -228,6 +231,35 @@ func initializeDockerCli(dockerCli *command.DockerCli, flags *pflag.FlagSet, opt
 	return dockerCli.Initialize(opts)
 }
 
+// addPluginEndpointFlags adds the flags setting the context endpoints declared
+// by CLI plugins to the command run with args, before its flags are parsed. The
+// CLI is not initialized yet, so the plugins are looked up in the directories
+// of the configuration file loaded from the --config directory.
+func addPluginEndpointFlags(root *cobra.Command, args []string) error {
+	cmd, _, err := root.Find(args)
+	if err != nil || cmd.Annotations[contextcmd.CommandAnnotationPluginEndpoints] == "" {
+		return nil
+	}
+	_, configDir := findAliasCommand(root.Flags(), args)
+	if configDir != "" {
+		// the metadata of the plugins is cached in the configuration directory
+		cliconfig.SetDir(configDir)
+	}
+	configFile, err := cliconfig.Load(cliconfig.Dir())
+	if err != nil {
+		// errors loading the config file are reported when initializing
+		// the cli
+		logrus.Debugf("loading plugins from the default directories: %v", err)
+		configFile = nil
+	}
+	plugins, err := pluginmanager.ListPluginsInDirs(cliconfig.PluginDirs(configFile), root)
+	if err != nil {
+		return err
+	}
+	contextcmd.AddPluginEndpointFlags(cmd, plugins)
+	return nil
+}
+
 func main() {
 	dockerCli, err := command.NewDockerCli()
 	if err != nil {
@@ -238,6 +270,21 @@ func main() {
 
 	cmd := newDockerCommand(dockerCli)
 
+	args, err := processAliases(cmd, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(dockerCli.Err(), err)
+		os.Exit(1)
+	}
+	// CLI plugins are run with os.Args, which must include the expansion of
+	// the aliases
+	os.Args = append(os.Args[:1], args...)
+	cmd.SetArgs(args)
+
+	if err := addPluginEndpointFlags(cmd, args); err != nil {
+		fmt.Fprintln(dockerCli.Err(), err)
+		os.Exit(1)
+	}
+
 	if err := cmd.Execute(); err != nil {
 		if sterr, ok := err.(cli.StatusError); ok {
 			if sterr.Status != "" {